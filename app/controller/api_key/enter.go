@@ -0,0 +1,4 @@
+package api_key
+
+type ApiKeyApi struct {
+}
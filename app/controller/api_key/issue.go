@@ -0,0 +1,57 @@
+package api_key
+
+import (
+	"gpm/app/model"
+	"gpm/app/service/apikey"
+	"gpm/common/res"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IssueApiKeyReq struct {
+	Name       string `json:"name" binding:"required,max=255"`
+	ExpireDays int    `json:"expireDays" binding:"min=0"` // 0=永不过期
+	// ServiceAccountID 为空时签发租户级 Key，否则签发服务账号 Key，可代替访问令牌
+	ServiceAccountID string   `json:"serviceAccountId"`
+	AllowIPs         []string `json:"allowIps"` // IP 或 CIDR，空表示不限制
+}
+
+// ApiKeyRes 签发结果，key 明文仅在签发时返回一次
+type ApiKeyRes struct {
+	Prefix   string `json:"prefix"`
+	Key      string `json:"key"`
+	ExpireAt int    `json:"expireAt"`
+}
+
+// IssueApiKeyView 为当前租户或其服务账号签发 API Key
+func (ApiKeyApi) IssueApiKeyView(c *gin.Context) {
+	var cr IssueApiKeyReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	if err := apikey.ValidAllowIPs(cr.AllowIPs); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	tenant := c.GetString("tenant")
+	key := model.ApiKey{TenantID: tenant, Name: cr.Name, AllowIPs: cr.AllowIPs}
+	if cr.ServiceAccountID != "" {
+		account, err := apikey.GetServiceAccount(c.Request.Context(), tenant, cr.ServiceAccountID)
+		if err != nil {
+			res.FailWithError(c, err)
+			return
+		}
+		key.ServiceAccountID = &account.ID
+	}
+	if cr.ExpireDays > 0 {
+		key.ExpireAt = int(time.Now().AddDate(0, 0, cr.ExpireDays).Unix())
+	}
+	raw, err := apikey.Issue(c.Request.Context(), &key)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, ApiKeyRes{Prefix: key.Prefix, Key: raw, ExpireAt: key.ExpireAt})
+}
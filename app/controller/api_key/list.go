@@ -0,0 +1,38 @@
+package api_key
+
+import (
+	"gpm/app/model"
+	"gpm/common"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiKeyListReq struct {
+	common.PageInfo
+	ServiceAccountID string `form:"serviceAccountId"`
+}
+
+// ApiKeyListView 当前租户的 API Key 列表
+func (ApiKeyApi) ApiKeyListView(c *gin.Context) {
+	var cr ApiKeyListReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	query := model.ApiKey{TenantID: c.GetString("tenant")}
+	if cr.ServiceAccountID != "" {
+		query.ServiceAccountID = &cr.ServiceAccountID
+	}
+	result, count, err := common.NewQueryBuilder(query, common.Options{
+		PageInfo:     cr.PageInfo,
+		Likes:        []string{"name", "prefix"},
+		DefaultOrder: "create_at:desc",
+		Context:      c.Request.Context(),
+	}).Build().GetResult()
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithList(c, result, count)
+}
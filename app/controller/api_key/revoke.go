@@ -0,0 +1,26 @@
+package api_key
+
+import (
+	"gpm/app/service/apikey"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RevokeApiKeyReq struct {
+	Prefix string `json:"prefix" binding:"required"`
+}
+
+// RevokeApiKeyView 吊销当前租户的 API Key
+func (ApiKeyApi) RevokeApiKeyView(c *gin.Context) {
+	var cr RevokeApiKeyReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	if err := apikey.Revoke(c.Request.Context(), c.GetString("tenant"), cr.Prefix); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "吊销成功")
+}
@@ -0,0 +1,32 @@
+package audit
+
+import (
+	"gpm/app/model"
+	"gpm/common"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CheckpointListReq struct {
+	common.PageInfo
+}
+
+// CheckpointListView 当前租户的签名检查点列表
+func (AuditApi) CheckpointListView(c *gin.Context) {
+	var cr CheckpointListReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	result, count, err := common.NewQueryBuilder(model.ActionLogCheckpoint{Tenant: c.GetString("tenant")}, common.Options{
+		PageInfo:     cr.PageInfo,
+		DefaultOrder: "seq:desc",
+		Context:      c.Request.Context(),
+	}).Build().GetResult()
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithList(c, result, count)
+}
@@ -0,0 +1,18 @@
+package audit
+
+import (
+	"gpm/app/service/audit"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportCheckpointView 导出当前租户带公钥的签名检查点，供外部离线核验；全部租户的导出使用 -export_checkpoint
+func (AuditApi) ExportCheckpointView(c *gin.Context) {
+	export, err := audit.ExportCheckpoints(c.Request.Context(), c.GetString("tenant"))
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, export)
+}
@@ -0,0 +1,18 @@
+package audit
+
+import (
+	"gpm/app/service/audit"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VerifyChainView 校验当前租户操作日志哈希链，返回第一处断裂；全部租户的校验使用 -verify_log
+func (AuditApi) VerifyChainView(c *gin.Context) {
+	result, err := audit.Verify(c.Request.Context(), c.GetString("tenant"))
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, result)
+}
@@ -1 +1,57 @@
 package doc
+
+import (
+	"gpm/app/model"
+	"gpm/common/res"
+	"gpm/global"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AddDocReq struct {
+	Name       string `json:"name" binding:"required,max=255"`
+	Path       string `json:"path" binding:"max=255"`
+	RouterPath string `json:"routerPath" binding:"max=255"`
+	Method     string `json:"method" binding:"max=10"`
+	Auth       bool   `json:"auth"`
+	Icon       string `json:"icon" binding:"max=255"`
+	Status     bool   `json:"status"`
+	ParentID   string `json:"parentId"`
+	Sort       int8   `json:"sort"`
+}
+
+// AddDocView 在当前租户创建文档，创建人即文档所有者，供策略条件 r.attr.Owner 判定
+func (DocApi) AddDocView(c *gin.Context) {
+	var cr AddDocReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	userId := c.GetString("userId")
+	if userId == "" {
+		res.FailWithMsg(c, "服务账号不能创建文档")
+		return
+	}
+	doc := model.Doc{
+		UserID:     userId,
+		Name:       cr.Name,
+		Path:       cr.Path,
+		RouterPath: cr.RouterPath,
+		Method:     cr.Method,
+		TenantID:   c.GetString("tenant"),
+		Auth:       cr.Auth,
+		Icon:       cr.Icon,
+		Status:     cr.Status,
+		ParentID:   cr.ParentID,
+		Sort:       cr.Sort,
+	}
+	db := global.DB.WithContext(c.Request.Context())
+	if doc.ParentID == "" {
+		db = db.Omit("parent_id")
+	}
+	if err := db.Create(&doc).Error; err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, doc)
+}
@@ -1 +1,33 @@
 package doc
+
+import (
+	"gpm/app/model"
+	"gpm/common"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DocListReq struct {
+	common.PageInfo
+}
+
+// DocListView 当前租户的文档列表
+func (DocApi) DocListView(c *gin.Context) {
+	var cr DocListReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	result, count, err := common.NewQueryBuilder(model.Doc{TenantID: c.GetString("tenant")}, common.Options{
+		PageInfo:     cr.PageInfo,
+		Likes:        []string{"name"},
+		DefaultOrder: "sort:asc",
+		Context:      c.Request.Context(),
+	}).Build().GetResult()
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithList(c, result, count)
+}
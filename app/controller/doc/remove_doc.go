@@ -1 +1,24 @@
 package doc
+
+import (
+	"gpm/app/model"
+	"gpm/common/res"
+	"gpm/global"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RemoveDocView 删除当前租户的文档，路由注册了所有者解析，可用 r.attr.Owner 限定只能删除自己的文档
+func (DocApi) RemoveDocView(c *gin.Context) {
+	result := global.DB.WithContext(c.Request.Context()).
+		Where("tenant_id = ? AND id = ?", c.GetString("tenant"), c.Param("id")).Delete(&model.Doc{})
+	if result.Error != nil {
+		res.FailWithError(c, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		res.FailWithMsg(c, "文档不存在")
+		return
+	}
+	res.SuccessWithMsg(c, "删除成功")
+}
@@ -1 +1,50 @@
 package doc
+
+import (
+	"gpm/app/model"
+	"gpm/common/res"
+	"gpm/global"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateDocReq struct {
+	Name       string `json:"name" binding:"required,max=255"`
+	Path       string `json:"path" binding:"max=255"`
+	RouterPath string `json:"routerPath" binding:"max=255"`
+	Method     string `json:"method" binding:"max=10"`
+	Auth       bool   `json:"auth"`
+	Icon       string `json:"icon" binding:"max=255"`
+	Status     bool   `json:"status"`
+	Sort       int8   `json:"sort"`
+}
+
+// UpdateDocView 更新当前租户的文档，路由注册了所有者解析，可用 r.attr.Owner 限定只能修改自己的文档
+func (DocApi) UpdateDocView(c *gin.Context) {
+	var cr UpdateDocReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	result := global.DB.WithContext(c.Request.Context()).Model(&model.Doc{}).
+		Where("tenant_id = ? AND id = ?", c.GetString("tenant"), c.Param("id")).
+		Updates(map[string]any{
+			"name":        cr.Name,
+			"path":        cr.Path,
+			"router_path": cr.RouterPath,
+			"method":      cr.Method,
+			"auth":        cr.Auth,
+			"icon":        cr.Icon,
+			"status":      cr.Status,
+			"sort":        cr.Sort,
+		})
+	if result.Error != nil {
+		res.FailWithError(c, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		res.FailWithMsg(c, "文档不存在")
+		return
+	}
+	res.SuccessWithMsg(c, "更新成功")
+}
@@ -2,22 +2,42 @@ package controller
 
 import (
 	"gpm/app/controller/api"
+	"gpm/app/controller/api_key"
+	"gpm/app/controller/audit"
 	"gpm/app/controller/doc"
+	"gpm/app/controller/grant"
+	"gpm/app/controller/health"
 	"gpm/app/controller/menu"
+	"gpm/app/controller/mfa"
+	"gpm/app/controller/oauth"
+	"gpm/app/controller/oidc"
 	"gpm/app/controller/permission"
 	"gpm/app/controller/role"
 	"gpm/app/controller/search"
+	"gpm/app/controller/service_account"
+	"gpm/app/controller/session"
+	"gpm/app/controller/sign_key"
 	"gpm/app/controller/tenant"
 	"gpm/app/controller/user"
 )
 
 type AdminApi struct {
-	UserApi       user.UserApi
-	SearchApi     search.SearchApi
-	ApiApi        api.ApiApi
-	DocApi        doc.DocApi
-	TenantApi     tenant.TenantApi
-	RoleApi       role.RoleApi
-	MenuApi       menu.MenuApi
-	PermissionApi permission.PermissionApi
+	UserApi           user.UserApi
+	SearchApi         search.SearchApi
+	ApiApi            api.ApiApi
+	DocApi            doc.DocApi
+	TenantApi         tenant.TenantApi
+	RoleApi           role.RoleApi
+	MenuApi           menu.MenuApi
+	PermissionApi     permission.PermissionApi
+	AuditApi          audit.AuditApi
+	SignKeyApi        sign_key.SignKeyApi
+	ApiKeyApi         api_key.ApiKeyApi
+	HealthApi         health.HealthApi
+	ServiceAccountApi service_account.ServiceAccountApi
+	OidcApi           oidc.OidcApi
+	OAuthApi          oauth.OAuthApi
+	MfaApi            mfa.MfaApi
+	SessionApi        session.SessionApi
+	GrantApi          grant.GrantApi
 }
@@ -0,0 +1,74 @@
+package grant
+
+import (
+	"gpm/app/service/grant"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApproveGrantReq struct {
+	Id      string `json:"id" binding:"required"`
+	Comment string `json:"comment" binding:"max=512"` // 审批意见
+}
+
+// ApproveGrantView 审批通过临时授权申请，申请人与被授权人不能审批
+func (GrantApi) ApproveGrantView(c *gin.Context) {
+	var cr ApproveGrantReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	userId := c.GetString("userId")
+	if userId == "" {
+		res.FailAuth(c)
+		return
+	}
+	c.Set("action", "审批通过临时授权 "+cr.Id)
+	err := grant.Approve(c.Request.Context(), c.GetString("tenant"), cr.Id, userId, cr.Comment)
+	if err != nil {
+		failGrant(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "临时授权已生效")
+}
+
+// RejectGrantView 驳回临时授权申请
+func (GrantApi) RejectGrantView(c *gin.Context) {
+	var cr ApproveGrantReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	userId := c.GetString("userId")
+	if userId == "" {
+		res.FailAuth(c)
+		return
+	}
+	c.Set("action", "驳回临时授权 "+cr.Id)
+	err := grant.Reject(c.Request.Context(), c.GetString("tenant"), cr.Id, userId, cr.Comment)
+	if err != nil {
+		failGrant(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "临时授权已驳回")
+}
+
+type RevokeGrantReq struct {
+	Id string `json:"id" binding:"required"`
+}
+
+// RevokeGrantView 提前回收临时授权或撤回待审批的申请
+func (GrantApi) RevokeGrantView(c *gin.Context) {
+	var cr RevokeGrantReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	c.Set("action", "回收临时授权 "+cr.Id)
+	if err := grant.Revoke(c.Request.Context(), c.GetString("tenant"), cr.Id); err != nil {
+		failGrant(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "临时授权已回收")
+}
@@ -0,0 +1,23 @@
+package grant
+
+import (
+	"errors"
+	"gpm/app/service/grant"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GrantApi struct {
+}
+
+// failGrant 业务错误直接提示，其余按系统错误处理
+func failGrant(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, grant.ErrGrantNotFound), errors.Is(err, grant.ErrGrantStatus),
+		errors.Is(err, grant.ErrSelfApprove), errors.Is(err, grant.ErrGrantExists), errors.Is(err, grant.ErrDuration):
+		res.FailWithMsg(c, err.Error())
+	default:
+		res.FailWithError(c, err)
+	}
+}
@@ -0,0 +1,67 @@
+package grant
+
+import (
+	"gpm/app/model"
+	"gpm/app/service/casbin_service"
+	"gpm/common"
+	"gpm/common/res"
+	"gpm/global"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type GrantListReq struct {
+	common.PageInfo
+	Status  string `form:"status" binding:"omitempty,oneof=pending active rejected expired revoked"`
+	Subject string `form:"subject"` // 完整主体，如 user:<id>
+}
+
+// GrantListView 当前租户的临时授权，审批人据此查看待审批申请
+func (GrantApi) GrantListView(c *gin.Context) {
+	var cr GrantListReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	where := global.DB.Where("tenant_id = ?", c.GetString("tenant"))
+	if cr.Subject != "" {
+		where = where.Where("subject = ?", cr.Subject)
+	}
+	grantList(c, cr, where)
+}
+
+// MyGrantListView 当前用户发起或获得的临时授权
+func (GrantApi) MyGrantListView(c *gin.Context) {
+	var cr GrantListReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	userId := c.GetString("userId")
+	if userId == "" {
+		res.FailToken(c)
+		return
+	}
+	where := global.DB.Where("tenant_id = ? AND (requester_id = ? OR subject = ?)",
+		c.GetString("tenant"), userId, casbin_service.UserSubject(userId))
+	grantList(c, cr, where)
+}
+
+func grantList(c *gin.Context, cr GrantListReq, where *gorm.DB) {
+	if cr.Status != "" {
+		where = where.Where("status = ?", cr.Status)
+	}
+	result, count, err := common.NewQueryBuilder(model.TemporaryGrant{}, common.Options{
+		PageInfo:     cr.PageInfo,
+		Likes:        []string{"reason"},
+		Where:        where,
+		DefaultOrder: "create_at:desc",
+		Context:      c.Request.Context(),
+	}).Build().GetResult()
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithList(c, result, count)
+}
@@ -0,0 +1,90 @@
+package grant
+
+import (
+	"gpm/app/model"
+	"gpm/app/service/casbin_service"
+	"gpm/app/service/grant"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 临时授权内容，kind=role 时填写 roleId，kind=policy 时填写 objType、objId、action
+type GrantItem struct {
+	Kind     string `json:"kind" binding:"required,oneof=role policy"`
+	RoleId   string `json:"roleId" binding:"required_if=Kind role"`
+	ObjId    string `json:"objId" binding:"required_if=Kind policy"`
+	ObjType  string `json:"objType" binding:"required_if=Kind policy,omitempty,oneof=api doc menu"`
+	Action   string `json:"action" binding:"required_if=Kind policy"`
+	Duration int    `json:"duration" binding:"required,min=60"` // 授权时长（秒），审批通过后开始计时
+	Reason   string `json:"reason" binding:"max=512"`
+}
+
+// grant 转换为临时授权，sub 为被授权主体
+func (cr GrantItem) grant(tenant, sub, requesterId string) *model.TemporaryGrant {
+	g := &model.TemporaryGrant{
+		TenantID:    tenant,
+		Kind:        cr.Kind,
+		Subject:     sub,
+		Duration:    cr.Duration,
+		Reason:      cr.Reason,
+		RequesterID: requesterId,
+	}
+	if cr.Kind == grant.KindRole {
+		g.Role = casbin_service.RoleSubject(cr.RoleId)
+	} else {
+		g.Obj = cr.ObjType + ":" + cr.ObjId
+		g.Act = cr.Action
+	}
+	return g
+}
+
+// RequestGrantView 当前用户为自己申请临时权限，需由他人审批
+func (GrantApi) RequestGrantView(c *gin.Context) {
+	var cr GrantItem
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	userId := c.GetString("userId")
+	if userId == "" {
+		res.FailToken(c)
+		return
+	}
+	g := cr.grant(c.GetString("tenant"), casbin_service.UserSubject(userId), userId)
+	c.Set("action", "申请临时授权")
+	if err := grant.Request(c.Request.Context(), g); err != nil {
+		failGrant(c, err)
+		return
+	}
+	res.SuccessWithData(c, g)
+}
+
+// 直接授予临时权限
+type GrantReq struct {
+	GrantItem
+	SubId   string `json:"subId" binding:"required"`
+	SubType string `json:"subType" binding:"required,oneof=user role"`
+}
+
+// GrantView 管理员直接授予他人临时权限，立即生效，到期自动回收
+func (GrantApi) GrantView(c *gin.Context) {
+	var cr GrantReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	// 需由具体用户授予，服务账号不可操作
+	userId := c.GetString("userId")
+	if userId == "" {
+		res.FailAuth(c)
+		return
+	}
+	g := cr.grant(c.GetString("tenant"), cr.SubType+":"+cr.SubId, userId)
+	c.Set("action", "授予临时权限")
+	if err := grant.Grant(c.Request.Context(), g); err != nil {
+		failGrant(c, err)
+		return
+	}
+	res.SuccessWithData(c, g)
+}
@@ -0,0 +1,4 @@
+package health
+
+type HealthApi struct {
+}
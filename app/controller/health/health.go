@@ -0,0 +1,21 @@
+package health
+
+import (
+	"gpm/common/res"
+	"gpm/global"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthView 健康检查，数据库不可用时返回失败
+func (HealthApi) HealthView(c *gin.Context) {
+	sqlDB, err := global.DB.DB()
+	if err == nil {
+		err = sqlDB.PingContext(c.Request.Context())
+	}
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "ok")
+}
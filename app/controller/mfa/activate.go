@@ -0,0 +1,51 @@
+package mfa
+
+import (
+	"gpm/app/service/jwt"
+	"gpm/app/service/mfa"
+	"gpm/app/service/session"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MfaActivateReq struct {
+	MfaToken string `json:"mfaToken"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// MfaActivateRes 恢复码仅返回一次；通过 MFA 令牌绑定时同时完成登录
+type MfaActivateRes struct {
+	RecoveryCodes []string       `json:"recoveryCodes"`
+	Token         *jwt.TokenPair `json:"token,omitempty"`
+}
+
+func (MfaApi) MfaActivateView(c *gin.Context) {
+	var cr MfaActivateReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	userId, tenant, claims, err := mfaUser(c, cr.MfaToken)
+	if err != nil {
+		res.FailWithMsgAndCode(c, res.FailTokenCode, err.Error())
+		return
+	}
+	codes, err := mfa.Activate(c.Request.Context(), userId, cr.Code)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	result := MfaActivateRes{RecoveryCodes: codes}
+	if claims != nil {
+		if err = mfa.ConsumeToken(c.Request.Context(), claims); err != nil {
+			res.FailWithMsgAndCode(c, res.FailTokenCode, err.Error())
+			return
+		}
+		if result.Token, err = session.Issue(c.Request.Context(), session.ClientFrom(c), tenant, userId, true); err != nil {
+			res.FailWithMsg(c, err.Error())
+			return
+		}
+	}
+	res.SuccessWithData(c, result)
+}
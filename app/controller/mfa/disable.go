@@ -0,0 +1,60 @@
+package mfa
+
+import (
+	"gpm/app/service/mfa"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MfaCodeReq struct {
+	Code string `json:"code" binding:"required"` // TOTP 验证码或恢复码
+}
+
+// MfaDisableView 停用 MFA，租户策略要求 MFA 时不允许停用
+func (MfaApi) MfaDisableView(c *gin.Context) {
+	var cr MfaCodeReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	userId := c.GetString("userId")
+	if userId == "" {
+		res.FailToken(c)
+		return
+	}
+	required, err := mfa.Required(c.Request.Context(), c.GetString("tenant"), userId)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	if required {
+		res.FailWithMsg(c, "租户策略要求启用 MFA")
+		return
+	}
+	if err = mfa.Disable(c.Request.Context(), userId, cr.Code); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "MFA 已停用")
+}
+
+// MfaRecoveryCodesView 重新生成恢复码，旧恢复码全部作废
+func (MfaApi) MfaRecoveryCodesView(c *gin.Context) {
+	var cr MfaCodeReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	userId := c.GetString("userId")
+	if userId == "" {
+		res.FailToken(c)
+		return
+	}
+	codes, err := mfa.RegenerateRecoveryCodes(c.Request.Context(), userId, cr.Code)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, codes)
+}
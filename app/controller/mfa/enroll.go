@@ -0,0 +1,44 @@
+package mfa
+
+import (
+	"gpm/app/model"
+	"gpm/app/service/mfa"
+	"gpm/common/res"
+	"gpm/global"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MfaEnrollReq struct {
+	MfaToken string `json:"mfaToken"` // 未登录时使用登录第一步返回的 MFA 令牌
+}
+
+type MfaEnrollRes struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"` // otpauth 地址，用于生成二维码
+}
+
+// MfaEnrollView 生成 TOTP 密钥，需调用 mfa/activate 验证后才启用
+func (MfaApi) MfaEnrollView(c *gin.Context) {
+	var cr MfaEnrollReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	userId, _, _, err := mfaUser(c, cr.MfaToken)
+	if err != nil {
+		res.FailWithMsgAndCode(c, res.FailTokenCode, err.Error())
+		return
+	}
+	var user model.User
+	if err = global.DB.WithContext(c.Request.Context()).Where("id = ?", userId).Take(&user).Error; err != nil {
+		res.FailWithMsg(c, "账号不存在")
+		return
+	}
+	secret, uri, err := mfa.Enroll(c.Request.Context(), &user)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, MfaEnrollRes{Secret: secret, Uri: uri})
+}
@@ -0,0 +1,28 @@
+package mfa
+
+import (
+	"errors"
+	"gpm/app/service/jwt"
+	"gpm/app/service/mfa"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MfaApi struct {
+}
+
+// mfaUser 获取当前用户：已登录用户，或登录第一步返回的 MFA 令牌（租户策略强制绑定时使用）
+// 通过 MFA 令牌时 claims 非空，签发令牌对前需调用 mfa.ConsumeToken
+func mfaUser(c *gin.Context, mfaToken string) (userId, tenant string, claims *jwt.MfaClaims, err error) {
+	if userId = c.GetString("userId"); userId != "" {
+		return userId, c.GetString("tenant"), nil, nil
+	}
+	if mfaToken == "" {
+		return "", "", nil, errors.New("未登录")
+	}
+	claims, err = mfa.ParseToken(c.Request.Context(), mfaToken)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return claims.Id, claims.Tenant, claims, nil
+}
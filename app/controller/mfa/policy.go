@@ -0,0 +1,48 @@
+package mfa
+
+import (
+	"errors"
+	"gpm/app/model"
+	"gpm/common/res"
+	"gpm/global"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MfaPolicyView 查看当前租户的 MFA 策略
+func (MfaApi) MfaPolicyView(c *gin.Context) {
+	tenant := c.GetString("tenant")
+	policy := model.MfaPolicy{TenantID: tenant}
+	err := global.DB.WithContext(c.Request.Context()).Where("tenant_id = ?", tenant).Take(&policy).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, policy)
+}
+
+type UpdateMfaPolicyReq struct {
+	RequireAll bool     `json:"requireAll"`
+	Roles      []string `json:"roles"`
+}
+
+// UpdateMfaPolicyView 设置当前租户的 MFA 策略，RequireAll 为 true 时要求全部用户
+func (MfaApi) UpdateMfaPolicyView(c *gin.Context) {
+	var cr UpdateMfaPolicyReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	policy := model.MfaPolicy{TenantID: c.GetString("tenant"), RequireAll: cr.RequireAll, Roles: cr.Roles}
+	err := global.DB.WithContext(c.Request.Context()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"require_all", "roles", "update_at"}),
+	}).Create(&policy).Error
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "更新成功")
+}
@@ -0,0 +1,49 @@
+package oauth
+
+import (
+	"gpm/app/model"
+	"gpm/app/service/oauth"
+	"gpm/common/res"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AddClientReq struct {
+	Name         string   `json:"name" binding:"required,max=255"`
+	RedirectURIs []string `json:"redirectUris" binding:"required,min=1"`
+	Public       bool     `json:"public"` // 公开客户端（SPA、移动端）无密钥，必须使用 PKCE
+}
+
+// AddClientRes 注册结果，clientSecret 仅在注册时返回一次
+type AddClientRes struct {
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+}
+
+// AddClientView 为当前租户注册应用
+func (OAuthApi) AddClientView(c *gin.Context) {
+	var cr AddClientReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	for _, uri := range cr.RedirectURIs {
+		if u, err := url.Parse(uri); err != nil || u.Scheme == "" || u.Host == "" || u.Fragment != "" {
+			res.FailValid(c, "回调地址格式错误: "+uri)
+			return
+		}
+	}
+	client := model.OAuthClient{
+		TenantID:     c.GetString("tenant"),
+		Name:         cr.Name,
+		RedirectURIs: cr.RedirectURIs,
+		Public:       cr.Public,
+	}
+	secret, err := oauth.RegisterClient(c.Request.Context(), &client)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, AddClientRes{ClientID: client.ClientID, ClientSecret: secret})
+}
@@ -0,0 +1,57 @@
+package oauth
+
+import (
+	"gpm/app/service/jwt"
+	"gpm/app/service/oauth"
+	"gpm/app/service/session"
+	"gpm/common/res"
+	"gpm/global"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthorizeView 授权端点，用户需已登录 gpm（Authorization 头或 access_token Cookie）
+func (OAuthApi) AuthorizeView(c *gin.Context) {
+	var cr oauth.AuthorizeReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	// 客户端或回调地址不合法时不能重定向，直接返回错误
+	client, err := oauth.CheckAuthorize(c.Request.Context(), cr)
+	if err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" {
+		token, _ = c.Cookie("access_token")
+	}
+	claims, err := jwt.NewJWT().ParseAccessToken(token)
+	if err == nil && claims.Act != nil {
+		// 模拟登录令牌不能向下游应用签发身份
+		res.FailWithMsgAndCode(c, res.FailAuthCode, "模拟登录期间不允许该操作")
+		return
+	}
+	if err == nil {
+		// 会话已吊销或账号已禁用时按未登录处理
+		err = session.Check(c.Request.Context(), claims.Sid, claims.Id)
+	}
+	if err != nil {
+		if loginURL := global.Config.OidcServer.LoginURL; loginURL != "" {
+			c.Redirect(http.StatusFound, loginURL+"?redirect="+url.QueryEscape(c.Request.URL.String()))
+			return
+		}
+		res.FailToken(c)
+		return
+	}
+	redirect, err := oauth.Authorize(c.Request.Context(), client, cr, claims.Id, claims.Sid)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	c.Redirect(http.StatusFound, redirect)
+}
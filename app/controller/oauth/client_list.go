@@ -0,0 +1,33 @@
+package oauth
+
+import (
+	"gpm/app/model"
+	"gpm/common"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClientListReq struct {
+	common.PageInfo
+}
+
+// ClientListView 当前租户注册的应用列表
+func (OAuthApi) ClientListView(c *gin.Context) {
+	var cr ClientListReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	result, count, err := common.NewQueryBuilder(model.OAuthClient{TenantID: c.GetString("tenant")}, common.Options{
+		PageInfo:     cr.PageInfo,
+		Likes:        []string{"name", "client_id"},
+		DefaultOrder: "create_at:desc",
+		Context:      c.Request.Context(),
+	}).Build().GetResult()
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithList(c, result, count)
+}
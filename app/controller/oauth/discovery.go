@@ -0,0 +1,25 @@
+package oauth
+
+import (
+	"gpm/app/service/keyring"
+	"gpm/app/service/oauth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DiscoveryView OIDC 发现文档，按标准格式返回
+func (OAuthApi) DiscoveryView(c *gin.Context) {
+	c.JSON(http.StatusOK, oauth.Discovery(oauth.Issuer(c.Request)))
+}
+
+// JwksView 公布令牌验签公钥
+func (OAuthApi) JwksView(c *gin.Context) {
+	set, err := keyring.JWKS(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, oauth.AsError(err))
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, set)
+}
@@ -0,0 +1,4 @@
+package oauth
+
+type OAuthApi struct {
+}
@@ -0,0 +1,33 @@
+package oauth
+
+import (
+	"gpm/app/model"
+	"gpm/common/res"
+	"gpm/global"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RemoveClientReq struct {
+	ClientID string `json:"clientId" binding:"required"`
+}
+
+// RemoveClientView 删除当前租户注册的应用
+func (OAuthApi) RemoveClientView(c *gin.Context) {
+	var cr RemoveClientReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	result := global.DB.WithContext(c.Request.Context()).
+		Where("tenant_id = ? AND client_id = ?", c.GetString("tenant"), cr.ClientID).Delete(&model.OAuthClient{})
+	if result.Error != nil {
+		res.FailWithError(c, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		res.FailWithMsg(c, "客户端不存在")
+		return
+	}
+	res.SuccessWithMsg(c, "删除成功")
+}
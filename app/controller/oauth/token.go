@@ -0,0 +1,38 @@
+package oauth
+
+import (
+	"errors"
+	"gpm/app/service/oauth"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokenView 令牌端点，支持 client_secret_basic 与 client_secret_post
+func (OAuthApi) TokenView(c *gin.Context) {
+	var cr oauth.TokenReq
+	if err := c.ShouldBind(&cr); err != nil {
+		c.JSON(http.StatusBadRequest, &oauth.Error{Code: "invalid_request", Description: err.Error()})
+		return
+	}
+	if id, secret, ok := c.Request.BasicAuth(); ok {
+		cr.ClientID, _ = url.QueryUnescape(id)
+		cr.ClientSecret, _ = url.QueryUnescape(secret)
+	}
+	c.Header("Cache-Control", "no-store")
+	result, err := oauth.Exchange(c.Request.Context(), oauth.Issuer(c.Request), cr)
+	if err != nil {
+		e := oauth.AsError(err)
+		status := http.StatusBadRequest
+		var oe *oauth.Error
+		if !errors.As(err, &oe) {
+			status = http.StatusInternalServerError
+		} else if e.Code == "invalid_client" {
+			status = http.StatusUnauthorized
+		}
+		c.JSON(status, e)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
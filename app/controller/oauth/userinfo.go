@@ -0,0 +1,31 @@
+package oauth
+
+import (
+	"gpm/app/service/oauth"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserinfoView 返回访问令牌对应的用户信息
+func (OAuthApi) UserinfoView(c *gin.Context) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok {
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		c.JSON(http.StatusUnauthorized, &oauth.Error{Code: "invalid_token", Description: "缺少访问令牌"})
+		return
+	}
+	claims, err := oauth.ParseAccessToken(c.Request.Context(), oauth.Issuer(c.Request), token)
+	if err != nil {
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		c.JSON(http.StatusUnauthorized, oauth.AsError(err))
+		return
+	}
+	info, err := oauth.Userinfo(c.Request.Context(), claims)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, oauth.AsError(err))
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
@@ -0,0 +1,53 @@
+package oidc
+
+import (
+	"gpm/app/service/mfa"
+	"gpm/app/service/oidc"
+	"gpm/app/service/session"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OidcCallbackReq struct {
+	Code             string `form:"code"`
+	State            string `form:"state" binding:"required"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+// OidcCallbackView 授权回调，完成登录后签发令牌对；需要 MFA 时与密码登录一样返回中间令牌
+func (OidcApi) OidcCallbackView(c *gin.Context) {
+	var cr OidcCallbackReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	if cr.Error != "" {
+		res.FailWithMsg(c, "外部登录失败: "+cr.Error+" "+cr.ErrorDescription)
+		return
+	}
+	user, tenant, err := oidc.Callback(c.Request.Context(), c.Param("provider"), cr.State, cr.Code)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	if tenant == "" {
+		tenant = c.GetString("tenant")
+	}
+	challenge, err := mfa.NewChallenge(c.Request.Context(), tenant, user.ID)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	if challenge != nil {
+		res.SuccessWithData(c, challenge)
+		return
+	}
+	pairToken, err := session.Issue(c.Request.Context(), session.ClientFrom(c), tenant, user.ID, false)
+	if err != nil {
+		res.FailWithMsg(c, err.Error())
+		return
+	}
+	res.SuccessWithData(c, pairToken)
+}
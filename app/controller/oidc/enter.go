@@ -0,0 +1,4 @@
+package oidc
+
+type OidcApi struct {
+}
@@ -0,0 +1,19 @@
+package oidc
+
+import (
+	"gpm/app/service/oidc"
+	"gpm/common/res"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OidcLoginView 跳转到外部身份提供方的授权页面
+func (OidcApi) OidcLoginView(c *gin.Context) {
+	url, err := oidc.AuthURL(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	c.Redirect(http.StatusFound, url)
+}
@@ -1,19 +1,27 @@
 package permission
 
 import (
+	"gpm/app/service/casbin_service"
 	"gpm/common/res"
 	"gpm/global"
 
 	"github.com/gin-gonic/gin"
 )
 
-// 给用户或者角色添加权限
+// 给用户、角色或者服务账号添加权限
 type AddPolicyReq struct {
 	SubId   string `json:"subId" binding:"required"`
-	SubType string `json:"subType" binding:"required,oneof=user role" `
-	ObjId   string `json:"objId" binding:"required"`
+	SubType string `json:"subType" binding:"required,oneof=user role service" `
+	ObjId   string `json:"objId" binding:"required"` // 接口为路由路径，如 /gpm/user，可使用以 * 结尾的路径模式，如 /gpm/doc/*
 	ObjType string `json:"objType" binding:"required,oneof=api doc menu"`
-	Action  string `json:"action" binding:"required,oneof=get post put delete read write owen"`
+	Action  string `json:"action" binding:"required"`                   // get post put delete read write owen，多个以 | 连接，* 表示全部
+	Cond    string `json:"cond"`                                        // 生效条件，如 r.attr.Hour >= 9 && r.attr.Hour < 18，为空表示无条件
+	Effect  string `json:"effect" binding:"omitempty,oneof=allow deny"` // 为空表示 allow，deny 优先于 allow
+}
+
+// rule 转换为 Casbin 策略 [sub, dom, obj, act, cond, eft]
+func (cr AddPolicyReq) rule(tenant string) []string {
+	return casbin_service.NewPolicy(cr.SubType+":"+cr.SubId, tenant, cr.ObjType+":"+cr.ObjId, cr.Action, cr.Cond, cr.Effect)
 }
 
 // 添加权限给用户或者角色
@@ -25,9 +33,16 @@ func (PermissionApi) AddPolicyView(c *gin.Context) {
 		return
 	}
 	tenant := c.GetString("tenant")
-	sub := cr.SubType + ":" + cr.SubId
-	obj := cr.ObjType + ":" + cr.ObjId
-	_, err = global.CasbinEnforcer.AddPolicy(sub, tenant, obj, cr.Action)
+	if err = checkSubject(c, tenant, cr.SubType, cr.SubId); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	rule := cr.rule(tenant)
+	if err = casbin_service.ValidatePolicy(rule); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	_, err = global.CasbinEnforcer.AddPolicy(rule)
 	if err != nil {
 		res.FailWithError(c, err)
 		return
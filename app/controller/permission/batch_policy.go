@@ -0,0 +1,109 @@
+package permission
+
+import (
+	"gpm/app/service/casbin_service"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 批量添加权限
+type BatchAddPolicyReq struct {
+	Rules []AddPolicyReq `json:"rules" binding:"required,min=1,dive"`
+}
+
+// 批量移除权限
+type BatchRemovePolicyReq struct {
+	Rules []RemovePolicyReq `json:"rules" binding:"required,min=1,dive"`
+}
+
+// BatchPolicyRes 实际生效的变更，已存在或不存在的规则不计入
+type BatchPolicyRes struct {
+	Added   [][]string `json:"added"`
+	Removed [][]string `json:"removed"`
+}
+
+// BatchAddPolicyView 批量添加权限，已存在的规则跳过
+func (PermissionApi) BatchAddPolicyView(c *gin.Context) {
+	var cr BatchAddPolicyReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	tenant := c.GetString("tenant")
+	rules := make([][]string, 0, len(cr.Rules))
+	for _, r := range cr.Rules {
+		if err := checkSubject(c, tenant, r.SubType, r.SubId); err != nil {
+			res.FailWithError(c, err)
+			return
+		}
+		rules = append(rules, r.rule(tenant))
+	}
+	added, err := casbin_service.AddPolicies(rules)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, BatchPolicyRes{Added: added, Removed: [][]string{}})
+}
+
+// BatchRemovePolicyView 批量移除权限，不存在的规则跳过
+func (PermissionApi) BatchRemovePolicyView(c *gin.Context) {
+	var cr BatchRemovePolicyReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	tenant := c.GetString("tenant")
+	rules := make([][]string, 0, len(cr.Rules))
+	for _, r := range cr.Rules {
+		rules = append(rules, r.rule(tenant))
+	}
+	removed, err := casbin_service.RemovePolicies(rules)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, BatchPolicyRes{Added: [][]string{}, Removed: removed})
+}
+
+// 替换时主体与租户由外层指定
+type ReplacePolicyItem struct {
+	ObjId   string `json:"objId" binding:"required"`
+	ObjType string `json:"objType" binding:"required,oneof=api doc menu"`
+	Action  string `json:"action" binding:"required"` // get post put delete read write owen，多个以 | 连接，* 表示全部
+	Cond    string `json:"cond"`
+	Effect  string `json:"effect" binding:"omitempty,oneof=allow deny"`
+}
+
+// 整体替换主体在当前租户下的权限，rules 为空表示清空
+type ReplacePolicyReq struct {
+	SubId   string              `json:"subId" binding:"required"`
+	SubType string              `json:"subType" binding:"required,oneof=user role service"`
+	Rules   []ReplacePolicyItem `json:"rules" binding:"dive"`
+}
+
+// ReplacePolicyView 用于角色编辑器一次性保存权限矩阵，返回新增与移除的规则
+func (PermissionApi) ReplacePolicyView(c *gin.Context) {
+	var cr ReplacePolicyReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	tenant := c.GetString("tenant")
+	if err := checkSubject(c, tenant, cr.SubType, cr.SubId); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	sub := cr.SubType + ":" + cr.SubId
+	rules := make([][]string, 0, len(cr.Rules))
+	for _, r := range cr.Rules {
+		rules = append(rules, casbin_service.NewPolicy(sub, tenant, r.ObjType+":"+r.ObjId, r.Action, r.Cond, r.Effect))
+	}
+	added, removed, err := casbin_service.ReplacePolicies(sub, tenant, rules)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, BatchPolicyRes{Added: added, Removed: removed})
+}
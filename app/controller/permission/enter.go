@@ -1,4 +1,20 @@
 package permission
 
+import (
+	"gpm/app/service/apikey"
+	"gpm/app/service/casbin_service"
+
+	"github.com/gin-gonic/gin"
+)
+
 type PermissionApi struct {
 }
+
+// checkSubject 服务账号必须属于当前租户
+func checkSubject(c *gin.Context, tenant, subType, subId string) error {
+	if subType != casbin_service.SubjectTypeService {
+		return nil
+	}
+	_, err := apikey.GetServiceAccount(c.Request.Context(), tenant, subId)
+	return err
+}
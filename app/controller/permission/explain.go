@@ -0,0 +1,72 @@
+package permission
+
+import (
+	"gpm/app/service/casbin_service"
+	"gpm/common/res"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 说明主体能否在租户内访问对象
+type ExplainReq struct {
+	SubId   string `form:"subId" binding:"required"`
+	SubType string `form:"subType" binding:"required,oneof=user role service"`
+	Obj     string `form:"obj" binding:"required"` // 完整对象，如 api:/gpm/user
+	Act     string `form:"act" binding:"required"`
+	Ip      string `form:"ip"`    // 条件判定使用的客户端 IP，为空时使用当前请求的 IP
+	Time    int64  `form:"time"`  // 条件判定使用的时间（Unix 秒），为空时使用当前时间
+	Owner   string `form:"owner"` // 条件判定使用的资源所有者ID
+}
+
+// ExplainView 返回判定结果、命中策略、角色继承链，拒绝时给出最近的缺失授权
+func (PermissionApi) ExplainView(c *gin.Context) {
+	var cr ExplainReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	result, err := casbin_service.Explain(cr.SubType+":"+cr.SubId, c.GetString("tenant"), cr.Obj, cr.Act, cr.attrs(c))
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, result)
+}
+
+// attrs 按请求参数构造条件判定属性，主体为用户时 UserId 取主体ID
+func (cr ExplainReq) attrs(c *gin.Context) casbin_service.Attrs {
+	ip := cr.Ip
+	if ip == "" {
+		ip = c.ClientIP()
+	}
+	var userId string
+	if cr.SubType == casbin_service.SubjectTypeUser {
+		userId = cr.SubId
+	}
+	now := time.Now()
+	if cr.Time > 0 {
+		now = time.Unix(cr.Time, 0)
+	}
+	return casbin_service.NewAttrsAt(userId, ip, cr.Owner, now)
+}
+
+type EffectivePolicyReq struct {
+	SubId   string `form:"subId" binding:"required"`
+	SubType string `form:"subType" binding:"required,oneof=user role service"`
+}
+
+// EffectivePolicyView 主体在租户下的全部有效权限，含经角色继承获得的权限
+func (PermissionApi) EffectivePolicyView(c *gin.Context) {
+	var cr EffectivePolicyReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	list, err := casbin_service.Permissions(cr.SubType+":"+cr.SubId, c.GetString("tenant"))
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, list)
+}
@@ -0,0 +1,67 @@
+package permission
+
+import (
+	"gpm/app/service/bundle"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportPolicyReq struct {
+	Format string `form:"format" binding:"omitempty,oneof=yaml csv"`
+}
+
+type ExportPolicyRes struct {
+	Format  string `json:"format"`
+	Content string `json:"content"`
+}
+
+// ExportPolicyView 导出当前租户的权限配置包
+func (PermissionApi) ExportPolicyView(c *gin.Context) {
+	var cr ExportPolicyReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	if cr.Format == "" {
+		cr.Format = bundle.FormatYAML
+	}
+	b, err := bundle.Export(c.Request.Context(), c.GetString("tenant"))
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	content, err := bundle.Encode(b, cr.Format)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, ExportPolicyRes{Format: cr.Format, Content: string(content)})
+}
+
+type ImportPolicyReq struct {
+	Format  string `json:"format" binding:"omitempty,oneof=yaml csv"`
+	Content string `json:"content" binding:"required"`
+	Apply   bool   `json:"apply"` // false 时只返回差异
+	Prune   bool   `json:"prune"`
+}
+
+// ImportPolicyView 导入权限配置包到当前租户，建议先预演确认差异
+func (PermissionApi) ImportPolicyView(c *gin.Context) {
+	var cr ImportPolicyReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	b, err := bundle.Decode([]byte(cr.Content), cr.Format)
+	if err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	diff, err := bundle.Import(c.Request.Context(), c.GetString("tenant"), b, bundle.Options{Apply: cr.Apply, Prune: cr.Prune})
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, diff)
+}
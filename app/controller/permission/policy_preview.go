@@ -0,0 +1,57 @@
+package permission
+
+import (
+	"gpm/app/model"
+	"gpm/app/service/casbin_service"
+	"gpm/common/res"
+	"gpm/global"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 预览接口授权覆盖的接口
+type PolicyPreviewReq struct {
+	ObjId  string `form:"objId" binding:"required"` // 接口路径或以 * 结尾的路径模式，如 /gpm/doc/*
+	Action string `form:"action"`                   // 操作集合，如 get|post，为空时不按请求方法过滤
+}
+
+// PolicyPreviewView 策略编辑器中预览授权对象覆盖当前租户下的哪些接口
+func (PermissionApi) PolicyPreviewView(c *gin.Context) {
+	var cr PolicyPreviewReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	obj := "api:" + cr.ObjId
+	if err := casbin_service.ValidateObject(obj); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	act := cr.Action
+	if act == "" {
+		act = casbin_service.AnyAction
+	}
+	if err := casbin_service.ValidateAction(act); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	var apis []model.Api
+	err := global.DB.WithContext(c.Request.Context()).
+		Where("tenant_id = ?", c.GetString("tenant")).Order("path").Find(&apis).Error
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	list := []model.Api{}
+	for _, a := range apis {
+		if !casbin_service.ObjMatch("api:"+a.Path, obj) {
+			continue
+		}
+		if !casbin_service.ActMatch(strings.ToLower(a.Method), act) {
+			continue
+		}
+		list = append(list, a)
+	}
+	res.SuccessWithList(c, list, int64(len(list)))
+}
@@ -3,34 +3,40 @@ package permission
 import (
 	"gpm/common/res"
 	"gpm/global"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-// 给用户或者角色添加权限
+// 移除用户、角色或者服务账号的权限
 type RemovePolicyReq struct {
 	SubId   string `json:"subId" binding:"required"`
-	SubType string `json:"subType" binding:"required,oneof=user role" `
+	SubType string `json:"subType" binding:"required,oneof=user role service" `
 	ObjId   string `json:"objId" binding:"required"`
 	ObjType string `json:"objType" binding:"required,oneof=api doc menu"`
-	Action  string `json:"action" binding:"required,oneof=get post put delete read write owen"`
+	Action  string `json:"action" binding:"required"`
+	Cond    string `json:"cond"`   // 为空时移除该授权的全部条件版本
+	Effect  string `json:"effect"` // 为空时 allow、deny 一并移除
 }
 
-// 添加权限给用户或者角色
+// rule 转换为 Casbin 策略过滤条件 [sub, dom, obj, act, cond, eft]，为空的字段不限
+func (cr RemovePolicyReq) rule(tenant string) []string {
+	return []string{cr.SubType + ":" + cr.SubId, tenant, cr.ObjType + ":" + cr.ObjId, cr.Action, strings.TrimSpace(cr.Cond), cr.Effect}
+}
+
+// 移除用户或者角色的权限
 func (PermissionApi) RemovePolicyView(c *gin.Context) {
-	var cr AddPolicyReq
+	var cr RemovePolicyReq
 	err := c.ShouldBindJSON(&cr)
 	if err != nil {
 		res.FailWithError(c, err)
 		return
 	}
 	tenant := c.GetString("tenant")
-	sub := cr.SubType + ":" + cr.SubId
-	obj := cr.ObjType + ":" + cr.ObjId
-	_, err = global.CasbinEnforcer.RemovePolicy(sub, tenant, obj, cr.Action)
+	_, err = global.CasbinEnforcer.RemoveFilteredPolicy(0, cr.rule(tenant)...)
 	if err != nil {
 		res.FailWithError(c, err)
 		return
 	}
-	res.SuccessWithMsg(c, "权限添加成功")
+	res.SuccessWithMsg(c, "权限移除成功")
 }
@@ -2,9 +2,11 @@ package file
 
 import (
 	"bufio"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
 	"gpm/common/res"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -50,10 +52,22 @@ func (File) FileSearchView(c *gin.Context) {
 		return
 	}
 
+	var reader io.Reader = file
+	// 已切分压缩的历史日志
+	if strings.HasSuffix(cr.FilePath, ".gz") {
+		gr, err := gzip.NewReader(file)
+		if err != nil {
+			res.FailWithMsg(c, "无效的日志文件")
+			return
+		}
+		defer gr.Close()
+		reader = gr
+	}
+
 	var results []map[string]interface{}
 
 	// 使用 bufio.Scanner 逐行读取
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(reader)
 	// 增大缓冲区以支持超长日志行（如大 SQL 或 JSON）
 	buf := make([]byte, 0, 64*1024)
 	scanner.Buffer(buf, 1024*1024) // 最大支持 1MB 的单行
@@ -73,7 +87,7 @@ func (File) FileSearchView(c *gin.Context) {
 		// 解析 JSON
 		var entry map[string]interface{}
 		if err = json.Unmarshal(line, &entry); err != nil {
-			logrus.WithContext(c.Request.Context()).Info("第 %d 行解析失败（跳过）: %s", lineNum, truncate(string(line), 200))
+			logrus.WithContext(c.Request.Context()).Infof("第 %d 行解析失败（跳过）: %s", lineNum, truncate(string(line), 200))
 			continue
 		}
 
@@ -0,0 +1,34 @@
+package service_account
+
+import (
+	"gpm/app/model"
+	"gpm/common/res"
+	"gpm/global"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AddServiceAccountReq struct {
+	Name   string `json:"name" binding:"required,max=255"`
+	Remark string `json:"remark" binding:"max=255"`
+}
+
+// AddServiceAccountView 在当前租户创建服务账号，返回的 id 即 Casbin 主体 service:<id> 中的 id
+func (ServiceAccountApi) AddServiceAccountView(c *gin.Context) {
+	var cr AddServiceAccountReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	account := model.ServiceAccount{
+		TenantID: c.GetString("tenant"),
+		Name:     cr.Name,
+		Remark:   cr.Remark,
+		Status:   true,
+	}
+	if err := global.DB.WithContext(c.Request.Context()).Create(&account).Error; err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, account)
+}
@@ -0,0 +1,4 @@
+package service_account
+
+type ServiceAccountApi struct {
+}
@@ -0,0 +1,26 @@
+package service_account
+
+import (
+	"gpm/app/service/apikey"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RemoveServiceAccountReq struct {
+	Id string `json:"id" binding:"required"`
+}
+
+// RemoveServiceAccountView 删除当前租户的服务账号，同时吊销其 Key 并移除其权限
+func (ServiceAccountApi) RemoveServiceAccountView(c *gin.Context) {
+	var cr RemoveServiceAccountReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	if err := apikey.RemoveServiceAccount(c.Request.Context(), c.GetString("tenant"), cr.Id); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "删除成功")
+}
@@ -0,0 +1,33 @@
+package service_account
+
+import (
+	"gpm/app/model"
+	"gpm/common"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ServiceAccountListReq struct {
+	common.PageInfo
+}
+
+// ServiceAccountListView 当前租户的服务账号列表
+func (ServiceAccountApi) ServiceAccountListView(c *gin.Context) {
+	var cr ServiceAccountListReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	result, count, err := common.NewQueryBuilder(model.ServiceAccount{TenantID: c.GetString("tenant")}, common.Options{
+		PageInfo:     cr.PageInfo,
+		Likes:        []string{"name", "remark"},
+		DefaultOrder: "create_at:desc",
+		Context:      c.Request.Context(),
+	}).Build().GetResult()
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithList(c, result, count)
+}
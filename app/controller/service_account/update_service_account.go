@@ -0,0 +1,38 @@
+package service_account
+
+import (
+	"gpm/app/model"
+	"gpm/app/service/apikey"
+	"gpm/common/res"
+	"gpm/global"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateServiceAccountReq struct {
+	Id     string `json:"id" binding:"required"`
+	Name   string `json:"name" binding:"required,max=255"`
+	Remark string `json:"remark" binding:"max=255"`
+	Status bool   `json:"status"` // 禁用后其全部 Key 立即失效
+}
+
+// UpdateServiceAccountView 更新当前租户的服务账号
+func (ServiceAccountApi) UpdateServiceAccountView(c *gin.Context) {
+	var cr UpdateServiceAccountReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	account, err := apikey.GetServiceAccount(c.Request.Context(), c.GetString("tenant"), cr.Id)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	err = global.DB.WithContext(c.Request.Context()).Model(&model.ServiceAccount{}).Where("id = ?", account.ID).
+		Updates(map[string]any{"name": cr.Name, "remark": cr.Remark, "status": cr.Status}).Error
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "更新成功")
+}
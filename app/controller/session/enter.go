@@ -0,0 +1,4 @@
+package session
+
+type SessionApi struct {
+}
@@ -0,0 +1,55 @@
+package session
+
+import (
+	"errors"
+	"gpm/app/service/session"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImpersonateReq struct {
+	UserId string `json:"userId" binding:"required"`
+}
+
+// ImpersonateView 以目标用户身份登录当前租户，用于客服排查问题
+func (SessionApi) ImpersonateView(c *gin.Context) {
+	var cr ImpersonateReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	actorId := c.GetString("userId")
+	if actorId == "" {
+		res.FailToken(c)
+		return
+	}
+	if c.GetString("actorId") != "" {
+		res.FailWithMsg(c, session.ErrImpersonateNested.Error())
+		return
+	}
+	result, err := session.Impersonate(c.Request.Context(), session.ClientFrom(c), c.GetString("tenant"), cr.UserId, actorId)
+	if errors.Is(err, session.ErrImpersonateSelf) || errors.Is(err, session.ErrImpersonateMember) || errors.Is(err, session.ErrImpersonateHigher) {
+		res.FailWithMsg(c, err.Error())
+		return
+	}
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, result)
+}
+
+// EndImpersonateView 结束模拟登录，当前模拟令牌随即失效
+func (SessionApi) EndImpersonateView(c *gin.Context) {
+	if c.GetString("actorId") == "" {
+		res.FailWithMsg(c, session.ErrNotImpersonating.Error())
+		return
+	}
+	err := session.EndImpersonate(c.Request.Context(), c.GetString("userId"), c.GetString("sid"))
+	if err != nil && !errors.Is(err, session.ErrSessionNotFound) {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "已结束模拟登录")
+}
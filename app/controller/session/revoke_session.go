@@ -0,0 +1,77 @@
+package session
+
+import (
+	"errors"
+	"gpm/app/service/casbin_service"
+	"gpm/app/service/session"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RevokeSessionReq struct {
+	Id  string `json:"id"`  // 会话标识，为空时吊销全部会话
+	All bool   `json:"all"` // 吊销全部会话时是否包含当前会话
+}
+
+// RevokeSessionView 当前用户吊销自己的会话
+func (SessionApi) RevokeSessionView(c *gin.Context) {
+	var cr RevokeSessionReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	userId := c.GetString("userId")
+	if userId == "" {
+		res.FailToken(c)
+		return
+	}
+	var err error
+	if cr.Id != "" {
+		err = session.Revoke(c.Request.Context(), userId, cr.Id, session.ReasonLogout)
+	} else {
+		except := c.GetString("sid")
+		if cr.All {
+			except = ""
+		}
+		err = session.RevokeAll(c.Request.Context(), userId, except, session.ReasonLogout)
+	}
+	if errors.Is(err, session.ErrSessionNotFound) {
+		res.FailWithMsg(c, err.Error())
+		return
+	}
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "会话已下线")
+}
+
+type RevokeUserSessionReq struct {
+	UserId string `json:"userId" binding:"required"`
+	Id     string `json:"id"` // 会话标识，为空时吊销该用户在当前租户下的全部会话
+}
+
+// RevokeUserSessionView 管理员吊销当前租户成员在该租户下的会话
+func (SessionApi) RevokeUserSessionView(c *gin.Context) {
+	var cr RevokeUserSessionReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	tenant := c.GetString("tenant")
+	if !casbin_service.IsMember(cr.UserId, tenant) {
+		res.FailWithMsg(c, "账号不存在")
+		return
+	}
+	err := session.RevokeInTenant(c.Request.Context(), cr.UserId, tenant, cr.Id, session.ReasonAdmin)
+	if errors.Is(err, session.ErrSessionNotFound) {
+		res.FailWithMsg(c, err.Error())
+		return
+	}
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "会话已下线")
+}
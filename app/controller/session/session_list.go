@@ -0,0 +1,79 @@
+package session
+
+import (
+	"gpm/app/model"
+	"gpm/app/service/casbin_service"
+	"gpm/app/service/session"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionRes 会话信息，Current 标记发起请求的会话
+type SessionRes struct {
+	Id            string `json:"id"`
+	TenantID      string `json:"tenantId"`
+	Device        string `json:"device"`
+	UserAgent     string `json:"userAgent"`
+	IP            string `json:"ip"`
+	ActorId       string `json:"actorId,omitempty"` // 模拟登录的真实操作人
+	CreateAt      int    `json:"createAt"`
+	LastRefreshAt int    `json:"lastRefreshAt"`
+	ExpireAt      int    `json:"expireAt"`
+	Current       bool   `json:"current"`
+}
+
+func sessionList(c *gin.Context, list []model.UserSession, err error) {
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	current := c.GetString("sid")
+	result := make([]SessionRes, 0, len(list))
+	for _, s := range list {
+		result = append(result, SessionRes{
+			Id:            s.ID,
+			TenantID:      s.TenantID,
+			Device:        s.Device,
+			UserAgent:     s.UserAgent,
+			IP:            s.IP,
+			ActorId:       s.ActorID,
+			CreateAt:      s.CreateAt,
+			LastRefreshAt: s.LastRefreshAt,
+			ExpireAt:      s.ExpireAt,
+			Current:       s.ID == current,
+		})
+	}
+	res.SuccessWithData(c, result)
+}
+
+// SessionListView 当前用户的登录会话
+func (SessionApi) SessionListView(c *gin.Context) {
+	userId := c.GetString("userId")
+	if userId == "" {
+		res.FailToken(c)
+		return
+	}
+	list, err := session.List(c.Request.Context(), userId)
+	sessionList(c, list, err)
+}
+
+type UserSessionListReq struct {
+	UserId string `form:"userId" binding:"required"`
+}
+
+// UserSessionListView 管理员查看当前租户成员在该租户下的登录会话
+func (SessionApi) UserSessionListView(c *gin.Context) {
+	var cr UserSessionListReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	tenant := c.GetString("tenant")
+	if !casbin_service.IsMember(cr.UserId, tenant) {
+		res.FailWithMsg(c, "账号不存在")
+		return
+	}
+	list, err := session.ListInTenant(c.Request.Context(), cr.UserId, tenant)
+	sessionList(c, list, err)
+}
@@ -0,0 +1,4 @@
+package sign_key
+
+type SignKeyApi struct {
+}
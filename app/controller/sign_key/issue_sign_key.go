@@ -0,0 +1,40 @@
+package sign_key
+
+import (
+	"gpm/app/service/signature"
+	"gpm/common/res"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IssueSignKeyReq struct {
+	Remark     string `json:"remark" binding:"max=255"`
+	ExpireDays int    `json:"expireDays" binding:"min=0"` // 0=永不过期
+}
+
+// SignKeyRes 签发结果，secret 仅在签发时返回一次
+type SignKeyRes struct {
+	KeyID    string `json:"keyId"`
+	Secret   string `json:"secret"`
+	ExpireAt int    `json:"expireAt"`
+}
+
+// IssueSignKeyView 为当前租户签发签名密钥
+func (SignKeyApi) IssueSignKeyView(c *gin.Context) {
+	var cr IssueSignKeyReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	expireAt := 0
+	if cr.ExpireDays > 0 {
+		expireAt = int(time.Now().AddDate(0, 0, cr.ExpireDays).Unix())
+	}
+	key, err := signature.IssueKey(c.Request.Context(), c.GetString("tenant"), cr.Remark, expireAt)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, SignKeyRes{KeyID: key.KeyID, Secret: key.Secret, ExpireAt: key.ExpireAt})
+}
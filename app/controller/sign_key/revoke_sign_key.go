@@ -0,0 +1,29 @@
+package sign_key
+
+import (
+	"gpm/app/service/signature"
+	"gpm/common/res"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RevokeSignKeyReq struct {
+	KeyID string `json:"keyId" binding:"required"`
+	Grace int    `json:"grace" binding:"min=0"` // 0=立即吊销，否则在宽限期后过期
+}
+
+// RevokeSignKeyView 吊销当前租户的签名密钥
+func (SignKeyApi) RevokeSignKeyView(c *gin.Context) {
+	var cr RevokeSignKeyReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	err := signature.RevokeKey(c.Request.Context(), c.GetString("tenant"), cr.KeyID, time.Duration(cr.Grace)*time.Second)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "吊销成功")
+}
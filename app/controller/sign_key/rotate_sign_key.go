@@ -0,0 +1,29 @@
+package sign_key
+
+import (
+	"gpm/app/service/signature"
+	"gpm/common/res"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RotateSignKeyReq struct {
+	Remark string `json:"remark" binding:"max=255"`
+	Grace  int    `json:"grace" binding:"min=0"` // 旧密钥宽限期（秒）
+}
+
+// RotateSignKeyView 为当前租户签发新密钥，旧密钥在宽限期内仍可验签
+func (SignKeyApi) RotateSignKeyView(c *gin.Context) {
+	var cr RotateSignKeyReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	key, err := signature.RotateKey(c.Request.Context(), c.GetString("tenant"), cr.Remark, time.Duration(cr.Grace)*time.Second)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithData(c, SignKeyRes{KeyID: key.KeyID, Secret: key.Secret, ExpireAt: key.ExpireAt})
+}
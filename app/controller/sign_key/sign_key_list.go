@@ -0,0 +1,33 @@
+package sign_key
+
+import (
+	"gpm/app/model"
+	"gpm/common"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SignKeyListReq struct {
+	common.PageInfo
+}
+
+// SignKeyListView 当前租户的签名密钥列表
+func (SignKeyApi) SignKeyListView(c *gin.Context) {
+	var cr SignKeyListReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	result, count, err := common.NewQueryBuilder(model.TenantSignKey{TenantID: c.GetString("tenant")}, common.Options{
+		PageInfo:     cr.PageInfo,
+		Likes:        []string{"key_id", "remark"},
+		DefaultOrder: "create_at:desc",
+		Context:      c.Request.Context(),
+	}).Build().GetResult()
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithList(c, result, count)
+}
@@ -3,7 +3,8 @@ package user
 import (
 	"github.com/gin-gonic/gin"
 	"gpm/app/model"
-	"gpm/app/service/jwt"
+	"gpm/app/service/mfa"
+	"gpm/app/service/session"
 	"gpm/common/res"
 	"gpm/common/util"
 	"gpm/global"
@@ -31,10 +32,29 @@ func (UserApi) UserLoginView(c *gin.Context) {
 		res.FailWithMsg(c, "密码错误")
 		return
 	}
+	if !user.Status {
+		res.FailWithMsg(c, "账号已禁用")
+		return
+	}
+	if user.VerifiedAt == 0 {
+		res.FailWithMsg(c, "账号未激活，请先完成邮箱验证")
+		return
+	}
+
+	tenant := c.GetString("tenant")
 
-	newJwt := jwt.NewJWT()
+	// 已启用 MFA 或租户策略要求 MFA 时，先返回中间令牌，由 login/mfa 签发令牌对
+	challenge, err := mfa.NewChallenge(c.Request.Context(), tenant, user.ID)
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	if challenge != nil {
+		res.SuccessWithData(c, challenge)
+		return
+	}
 
-	pairToken, err := newJwt.GenPairToken(c.GetString("tenant"), user.ID)
+	pairToken, err := session.Issue(c.Request.Context(), session.ClientFrom(c), tenant, user.ID, false)
 	if err != nil {
 		res.FailWithMsg(c, err.Error())
 		return
@@ -0,0 +1,43 @@
+package user
+
+import (
+	"gpm/app/service/mfa"
+	"gpm/app/service/session"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserMfaLoginReq struct {
+	MfaToken string `json:"mfaToken" binding:"required"`
+	Code     string `json:"code" binding:"required"` // TOTP 验证码或恢复码
+}
+
+// UserMfaLoginView 登录第二步，校验 MFA 后签发令牌对
+func (UserApi) UserMfaLoginView(c *gin.Context) {
+	var cr UserMfaLoginReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	claims, err := mfa.ParseToken(c.Request.Context(), cr.MfaToken)
+	if err != nil {
+		res.FailWithMsgAndCode(c, res.FailTokenCode, err.Error())
+		return
+	}
+	if err = mfa.Verify(c.Request.Context(), claims.Id, cr.Code); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	// 中间令牌只能换取一次令牌对
+	if err = mfa.ConsumeToken(c.Request.Context(), claims); err != nil {
+		res.FailWithMsgAndCode(c, res.FailTokenCode, err.Error())
+		return
+	}
+	pairToken, err := session.Issue(c.Request.Context(), session.ClientFrom(c), claims.Tenant, claims.Id, true)
+	if err != nil {
+		res.FailWithMsg(c, err.Error())
+		return
+	}
+	res.SuccessWithData(c, pairToken)
+}
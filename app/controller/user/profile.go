@@ -0,0 +1,104 @@
+package user
+
+import (
+	"gpm/app/model"
+	"gpm/app/service/session"
+	"gpm/common/res"
+	"gpm/common/util"
+	"gpm/global"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UserProfileView 当前登录用户资料
+func (UserApi) UserProfileView(c *gin.Context) {
+	userId := c.GetString("userId")
+	if userId == "" {
+		res.FailToken(c)
+		return
+	}
+	var user model.User
+	if err := global.DB.WithContext(c.Request.Context()).Where("id = ?", userId).Take(&user).Error; err != nil {
+		res.FailWithMsg(c, "账号不存在")
+		return
+	}
+	res.SuccessWithData(c, user)
+}
+
+type UpdateProfileReq struct {
+	Nickname string `json:"nickname" binding:"max=255"`
+	Avatar   string `json:"avatar" binding:"max=255"`
+	Phone    string `json:"phone" binding:"max=32"`
+	Address  string `json:"address" binding:"max=255"`
+	Sex      bool   `json:"sex"`
+}
+
+// UpdateProfileView 修改当前登录用户资料
+func (UserApi) UpdateProfileView(c *gin.Context) {
+	var cr UpdateProfileReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	userId := c.GetString("userId")
+	if userId == "" {
+		res.FailToken(c)
+		return
+	}
+	err := global.DB.WithContext(c.Request.Context()).Model(&model.User{}).Where("id = ?", userId).Updates(map[string]any{
+		"nickname": cr.Nickname,
+		"avatar":   cr.Avatar,
+		"phone":    cr.Phone,
+		"address":  cr.Address,
+		"sex":      cr.Sex,
+	}).Error
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "更新成功")
+}
+
+type UpdatePasswordReq struct {
+	OldPassword string `json:"oldPassword" binding:"required"`
+	Password    string `json:"password" binding:"required,min=5,max=16"`
+}
+
+// UpdatePasswordView 修改当前登录用户密码
+func (UserApi) UpdatePasswordView(c *gin.Context) {
+	var cr UpdatePasswordReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	userId := c.GetString("userId")
+	if userId == "" {
+		res.FailToken(c)
+		return
+	}
+	var user model.User
+	if err := global.DB.WithContext(c.Request.Context()).Where("id = ?", userId).Take(&user).Error; err != nil {
+		res.FailWithMsg(c, "账号不存在")
+		return
+	}
+	if util.Md5([]byte(cr.OldPassword+user.Salt)) != user.Password {
+		res.FailWithMsg(c, "原密码错误")
+		return
+	}
+	salt := uuid.New().String()
+	err := global.DB.WithContext(c.Request.Context()).Model(&user).Updates(map[string]any{
+		"password": util.Md5([]byte(cr.Password + salt)),
+		"salt":     salt,
+	}).Error
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	// 其余设备下线，保留当前会话
+	if err = session.RevokeAll(c.Request.Context(), userId, c.GetString("sid"), session.ReasonPassword); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "密码修改成功")
+}
@@ -0,0 +1,27 @@
+package user
+
+import (
+	"gpm/app/service/session"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserRefreshReq struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// UserRefreshView 使用刷新令牌换取新的令牌对，会话已吊销时需重新登录
+func (UserApi) UserRefreshView(c *gin.Context) {
+	var cr UserRefreshReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	pairToken, err := session.Refresh(c.Request.Context(), cr.RefreshToken)
+	if err != nil {
+		res.FailWithMsgAndCode(c, res.FailTokenCode, err.Error())
+		return
+	}
+	res.SuccessWithData(c, pairToken)
+}
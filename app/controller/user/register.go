@@ -2,6 +2,8 @@ package user
 
 import (
 	"gpm/app/model"
+	"gpm/app/service/account"
+	"gpm/app/service/log"
 	"gpm/common/res"
 	"gpm/common/util"
 	"gpm/global"
@@ -29,14 +31,20 @@ func (UserApi) UserRegisterView(c *gin.Context) {
 	}
 	salt := uuid.New().String()
 	hash := util.Md5([]byte(cr.Password + salt))
-	err := global.DB.WithContext(c.Request.Context()).Create(&model.User{
+	user = model.User{
 		Email:    cr.Email,
 		Password: hash,
 		Salt:     salt,
-	}).Error
+		Status:   true,
+	}
+	err := global.DB.WithContext(c.Request.Context()).Create(&user).Error
 	if err != nil {
 		res.FailWithMsg(c, err.Error())
 		return
 	}
-	res.FailWithMsg(c, "注册成功")
+	// 邮箱验证后才能登录，邮件发送失败时可通过 verify/resend 重发
+	if err = account.SendVerifyEmail(c.Request.Context(), &user); err != nil {
+		log.Ctx(c.Request.Context()).Warnf("验证邮件发送失败: %s", err)
+	}
+	res.SuccessWithMsg(c, "注册成功，请查收验证邮件")
 }
@@ -0,0 +1,41 @@
+package user
+
+import (
+	"gpm/app/service/account"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserForgotPasswordView 发送重置密码邮件，无论账号是否存在都返回相同结果
+func (UserApi) UserForgotPasswordView(c *gin.Context) {
+	var cr UserEmailReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	if err := account.SendResetEmail(c.Request.Context(), cr.Email); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "如果该邮箱已注册，重置密码邮件已发送")
+}
+
+type UserResetPasswordReq struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=5,max=16"`
+}
+
+// UserResetPasswordView 使用邮件中的令牌设置新密码
+func (UserApi) UserResetPasswordView(c *gin.Context) {
+	var cr UserResetPasswordReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	if err := account.ResetPassword(c.Request.Context(), cr.Token, cr.Password); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "密码重置成功")
+}
@@ -0,0 +1,111 @@
+package user
+
+import (
+	"gpm/app/model"
+	"gpm/app/service/casbin_service"
+	"gpm/app/service/session"
+	"gpm/common"
+	"gpm/common/res"
+	"gpm/global"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserListReq struct {
+	common.PageInfo
+	Status *bool `form:"status"`
+}
+
+// UserListView 当前租户的成员列表，key 按用户名、邮箱、手机号模糊搜索
+func (UserApi) UserListView(c *gin.Context) {
+	var cr UserListReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	where := global.DB.Where("")
+	if cr.Status != nil {
+		where = where.Where("status = ?", *cr.Status)
+	}
+	ids, err := casbin_service.MemberIds(c.GetString("tenant"))
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	if len(ids) == 0 {
+		res.SuccessWithList(c, []model.User{}, 0)
+		return
+	}
+	where = where.Where("id IN ?", ids)
+	result, count, err := common.NewQueryBuilder(model.User{}, common.Options{
+		PageInfo:     cr.PageInfo,
+		Likes:        []string{"username", "email", "phone"},
+		Where:        where,
+		DefaultOrder: "create_at:desc",
+		Context:      c.Request.Context(),
+	}).Build().GetResult()
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithList(c, result, count)
+}
+
+type UserDetailReq struct {
+	Id string `form:"id" binding:"required"`
+}
+
+// UserDetailView 当前租户成员的详情
+func (UserApi) UserDetailView(c *gin.Context) {
+	var cr UserDetailReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	if !casbin_service.IsMember(cr.Id, c.GetString("tenant")) {
+		res.FailWithMsg(c, "账号不存在")
+		return
+	}
+	var user model.User
+	if err := global.DB.WithContext(c.Request.Context()).Where("id = ?", cr.Id).Take(&user).Error; err != nil {
+		res.FailWithMsg(c, "账号不存在")
+		return
+	}
+	res.SuccessWithData(c, user)
+}
+
+type UpdateUserStatusReq struct {
+	Id     string `json:"id" binding:"required"`
+	Status bool   `json:"status"`
+}
+
+// UpdateUserStatusView 启用或禁用账号，禁用后无法登录任何租户
+// 账号状态是全局的，只能由平台租户的管理员修改；租户管理员将成员移出租户即可
+func (UserApi) UpdateUserStatusView(c *gin.Context) {
+	var cr UpdateUserStatusReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	platform := global.Config.System.PlatformTenant
+	if platform == "" || c.GetString("tenant") != platform {
+		res.FailWithMsgAndCode(c, res.FailAuthCode, "仅平台租户可修改账号状态")
+		return
+	}
+	result := global.DB.WithContext(c.Request.Context()).Model(&model.User{}).Where("id = ?", cr.Id).Update("status", cr.Status)
+	if result.Error != nil {
+		res.FailWithError(c, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		res.FailWithMsg(c, "账号不存在")
+		return
+	}
+	if !cr.Status {
+		if err := session.RevokeAll(c.Request.Context(), cr.Id, "", session.ReasonDisabled); err != nil {
+			res.FailWithError(c, err)
+			return
+		}
+	}
+	res.SuccessWithMsg(c, "更新成功")
+}
@@ -0,0 +1,114 @@
+package user
+
+import (
+	"gpm/app/model"
+	"gpm/app/service/casbin_service"
+	"gpm/common/res"
+	"gpm/global"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserTenantReq struct {
+	UserId string `form:"userId" binding:"required"`
+}
+
+// UserTenantRes 用户所属租户及在该租户下的角色
+type UserTenantRes struct {
+	TenantID string   `json:"tenantId"`
+	Roles    []string `json:"roles"`
+}
+
+// UserTenantListView 用户在当前租户下的角色，成员关系以 Casbin 角色分配为准
+func (UserApi) UserTenantListView(c *gin.Context) {
+	var cr UserTenantReq
+	if err := c.ShouldBindQuery(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	rules, err := global.CasbinEnforcer.GetFilteredGroupingPolicy(0, casbin_service.UserSubject(cr.UserId), "", c.GetString("tenant"))
+	if err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	list := []UserTenantRes{}
+	index := map[string]int{}
+	for _, rule := range rules {
+		if len(rule) < 3 {
+			continue
+		}
+		role := strings.TrimPrefix(rule[1], casbin_service.SubjectTypeRole+":")
+		i, ok := index[rule[2]]
+		if !ok {
+			i = len(list)
+			index[rule[2]] = i
+			list = append(list, UserTenantRes{TenantID: rule[2]})
+		}
+		list[i].Roles = append(list[i].Roles, role)
+	}
+	res.SuccessWithData(c, list)
+}
+
+type AddUserTenantReq struct {
+	UserId  string   `json:"userId" binding:"required"`
+	RoleIds []string `json:"roleIds" binding:"required,min=1"`
+}
+
+// AddUserTenantView 将用户加入当前租户并分配角色
+func (UserApi) AddUserTenantView(c *gin.Context) {
+	var cr AddUserTenantReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	tenant := c.GetString("tenant")
+	db := global.DB.WithContext(c.Request.Context())
+	var user model.User
+	if err := db.Where("id = ?", cr.UserId).Take(&user).Error; err != nil {
+		res.FailWithMsg(c, "账号不存在")
+		return
+	}
+	var count int64
+	if err := db.Model(&model.Role{}).Where("tenant_id = ? AND id IN ?", tenant, cr.RoleIds).Count(&count).Error; err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	if int(count) != len(cr.RoleIds) {
+		res.FailValid(c, "角色不存在或不属于该租户")
+		return
+	}
+	rules := make([][]string, 0, len(cr.RoleIds))
+	for _, roleId := range cr.RoleIds {
+		rules = append(rules, []string{casbin_service.UserSubject(user.ID), casbin_service.RoleSubject(roleId), tenant})
+	}
+	if _, err := global.CasbinEnforcer.AddGroupingPolicies(rules); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "添加成功")
+}
+
+type RemoveUserTenantReq struct {
+	UserId string `json:"userId" binding:"required"`
+}
+
+// RemoveUserTenantView 将用户移出当前租户，同时移除其在该租户下的角色与直接授权
+func (UserApi) RemoveUserTenantView(c *gin.Context) {
+	var cr RemoveUserTenantReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	tenant := c.GetString("tenant")
+	sub := casbin_service.UserSubject(cr.UserId)
+	if _, err := global.CasbinEnforcer.DeleteRolesForUserInDomain(sub, tenant); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	if _, err := global.CasbinEnforcer.RemoveFilteredPolicy(0, sub, tenant); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "移除成功")
+}
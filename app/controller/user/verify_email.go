@@ -0,0 +1,44 @@
+package user
+
+import (
+	"gpm/app/service/account"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserVerifyEmailReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// UserVerifyEmailView 使用邮件中的令牌激活账号
+func (UserApi) UserVerifyEmailView(c *gin.Context) {
+	var cr UserVerifyEmailReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	if err := account.VerifyEmail(c.Request.Context(), cr.Token); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "邮箱验证成功")
+}
+
+type UserEmailReq struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// UserResendVerifyView 重发验证邮件，无论账号是否存在都返回相同结果
+func (UserApi) UserResendVerifyView(c *gin.Context) {
+	var cr UserEmailReq
+	if err := c.ShouldBindJSON(&cr); err != nil {
+		res.FailValid(c, err.Error())
+		return
+	}
+	if err := account.ResendVerifyEmail(c.Request.Context(), cr.Email); err != nil {
+		res.FailWithError(c, err)
+		return
+	}
+	res.SuccessWithMsg(c, "如果该邮箱已注册且未验证，验证邮件已发送")
+}
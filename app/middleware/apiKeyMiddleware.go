@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"gpm/app/service/apikey"
+	"gpm/app/service/log"
+	"gpm/common/res"
+	"gpm/common/sign"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authApiKey 校验 API Key 并写入租户与主体，失败时中断请求并返回 true
+// 未携带租户头时使用 Key 所属租户；服务账号 Key 同时作为 Casbin 主体
+func authApiKey(c *gin.Context, raw string) (failed bool) {
+	key, err := apikey.Verify(c.Request.Context(), raw, c.ClientIP())
+	if err != nil {
+		res.FailWithMsgAndCode(c, res.FailTokenCode, err.Error())
+		c.Abort()
+		return true
+	}
+	tenant := c.GetHeader(sign.HeaderTenant)
+	if tenant != "" && tenant != key.TenantID {
+		res.FailWithMsgAndCode(c, res.FailTokenCode, "API Key 与租户不匹配")
+		c.Abort()
+		return true
+	}
+	ctx := c.Request.Context()
+	if tenant == "" {
+		c.Request.Header.Set(sign.HeaderTenant, key.TenantID)
+		c.Set("tenant", key.TenantID)
+		ctx = log.WithTenant(ctx, key.TenantID)
+	}
+	if sub := apikey.Subject(key); sub != "" {
+		ctx = log.WithUserId(ctx, sub)
+		c.Set("sub", sub)
+		c.Set("serviceAccountId", *key.ServiceAccountID)
+	}
+	c.Request = c.Request.WithContext(ctx)
+	c.Set("apiKey", key)
+	c.Set("tenantVerified", true)
+	return false
+}
@@ -2,22 +2,48 @@ package middleware
 
 import (
 	"bytes"
-	"crypto/sha256"
+	"crypto/hmac"
 	"encoding/base64"
-	"encoding/json"
-	"fmt"
+	"errors"
+	"gpm/app/service/apikey"
+	"gpm/app/service/metrics"
+	"gpm/app/service/replay"
+	"gpm/app/service/signature"
 	"gpm/common/res"
+	"gpm/common/sign"
 	"gpm/global"
 	"io"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// ArgsCheckMiddleware 请求来源校验，按路径规则决定是否校验，机器客户端可改用 API Key
 func ArgsCheckMiddleware(c *gin.Context) {
-	tenant := c.GetHeader("tenant")
-	timestamp := c.GetHeader("timestamp")
-	signature := c.GetHeader("signature")
-	if tenant == "" || timestamp == "" || signature == "" {
+	mode := argsCheckMode(c.Request.URL.Path)
+	if mode == ArgsCheckOff {
+		return
+	}
+	if raw := c.GetHeader(apikey.HeaderApiKey); raw != "" {
+		if authApiKey(c, raw) {
+			metrics.SignatureFailures.WithLabelValues("invalid_api_key").Inc()
+		}
+		return
+	}
+	if mode == ArgsCheckOptional && c.GetHeader(sign.HeaderSignature) == "" {
+		return
+	}
+	checkSignature(c)
+}
+
+// checkSignature 校验请求签名、时间窗口与 nonce
+func checkSignature(c *gin.Context) {
+	tenant := c.GetHeader(sign.HeaderTenant)
+	timestamp := c.GetHeader(sign.HeaderTimestamp)
+	signStr := c.GetHeader(sign.HeaderSignature)
+	nonce := c.GetHeader(sign.HeaderNonce)
+	if tenant == "" || timestamp == "" || signStr == "" || nonce == "" {
+		metrics.SignatureFailures.WithLabelValues("missing_header").Inc()
 		res.FailValid(c, "请求信息不全")
 		c.Abort()
 		return
@@ -30,49 +56,75 @@ func ArgsCheckMiddleware(c *gin.Context) {
 	//	return
 	//}
 
-	prefix := global.Config.ArgsCheck.Prefix
-	suffix := global.Config.ArgsCheck.Suffix
-	url := c.Request.RequestURI
+	window := replay.DefaultWindow
+	if global.Config.ArgsCheck.Window > 0 {
+		window = time.Duration(global.Config.ArgsCheck.Window) * time.Second
+	}
+	if err := replay.CheckTimestamp(timestamp, window); err != nil {
+		metrics.SignatureFailures.WithLabelValues("expired_timestamp").Inc()
+		res.FailWithMsgAndCode(c, res.FailExpireCode, err.Error())
+		c.Abort()
+		return
+	}
 
 	var requestBody []byte
 	if c.Request.Body != nil {
 		requestBody, _ = c.GetRawData()
 		c.Request.Body = io.NopCloser(bytes.NewReader(requestBody)) // 恢复 Body 供后续使用
 	}
-	// --- 核心修改点：使用“解析再封装”方法规范化请求体 ---
-	if len(requestBody) != 0 {
-		// 1. 定义一个变量来存储解析后的JSON数据
-		var jsonData interface{}
-		// 2. 使用 json.Unmarshal 解析原始请求体
-		// 使用 json.NewDecoder 更适合处理流式数据，但这里 c.GetString() 已经是字符串，Unmarshal 更直接
-		err := json.Unmarshal(requestBody, &jsonData)
-		if err != nil {
-			// 如果JSON解析失败，说明请求体格式不合法，应拒绝请求
-			res.FailValid(c, "请求体格式不合法: "+err.Error())
-			return
-		}
-		// 3. 使用 json.Marshal 将解析后的数据重新序列化
-		// Marshal 会生成一个紧凑的、无多余空格、键已排序的JSON字符串
-		requestBody, err = json.Marshal(jsonData)
+	// 规范化规则与客户端 SDK 共用，见 common/sign
+	canonicalBody, err := sign.CanonicalBody(requestBody)
+	if err != nil {
+		// 如果JSON解析失败，说明请求体格式不合法，应拒绝请求
+		metrics.SignatureFailures.WithLabelValues("invalid_body").Inc()
+		res.FailValid(c, err.Error())
+		c.Abort()
+		return
+	}
+	message := sign.Message(tenant, c.Request.RequestURI, canonicalBody, timestamp, nonce)
+	var expected []byte
+	if keyId := c.GetHeader(sign.HeaderKeyID); keyId != "" {
+		// 租户密钥：HMAC-SHA256
+		key, err := signature.ActiveKey(c.Request.Context(), tenant, keyId)
 		if err != nil {
-			res.FailWithMsg(c, "服务器内部错误: 无法序列化JSON")
+			metrics.SignatureFailures.WithLabelValues("invalid_key").Inc()
+			res.FailValid(c, err.Error())
+			c.Abort()
 			return
 		}
+		expected = sign.HMACBytes(key.Secret, message)
+	} else if global.Config.ArgsCheck.Legacy {
+		// 旧版全局前后缀：SHA-256
+		expected = sign.LegacyBytes(global.Config.ArgsCheck.Prefix, global.Config.ArgsCheck.Suffix, message)
+	} else {
+		metrics.SignatureFailures.WithLabelValues("missing_header").Inc()
+		res.FailValid(c, "缺少 key-id")
+		c.Abort()
+		return
 	}
-	signatureStr := prefix + tenant + url + string(requestBody) + timestamp + suffix
-	fmt.Println(signatureStr)
-	hash := sha256.New()
-	hash.Write([]byte(signatureStr))
-	hashSum := hash.Sum(nil)
-	signatureHash, err := base64.StdEncoding.DecodeString(signature)
+	signatureHash, err := base64.StdEncoding.DecodeString(signStr)
 	if err != nil {
+		metrics.SignatureFailures.WithLabelValues("malformed_signature").Inc()
 		res.FailWithMsg(c, "sign解析失败")
 		c.Abort()
 		return
 	}
-	if string(signatureHash) != string(hashSum) {
+	if !hmac.Equal(signatureHash, expected) {
+		metrics.SignatureFailures.WithLabelValues("mismatch").Inc()
 		res.FailValid(c, "参数校验失败")
 		c.Abort()
 		return
 	}
+	// 签名通过后再记录 nonce，避免伪造请求占用合法 nonce
+	if err = replay.CheckNonce(tenant, nonce, window); err != nil {
+		if errors.Is(err, replay.ErrReplay) {
+			metrics.SignatureFailures.WithLabelValues("replay").Inc()
+			res.FailWithCode(c, res.FailReplayCode)
+		} else {
+			res.FailValid(c, err.Error())
+		}
+		c.Abort()
+		return
+	}
+	c.Set("tenantVerified", true)
 }
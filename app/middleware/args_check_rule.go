@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"gpm/conf"
+	"gpm/global"
+	"path"
+	"strings"
+)
+
+// 签名校验模式
+const (
+	ArgsCheckRequired = "required" // 必须通过签名或 API Key 认证
+	ArgsCheckOptional = "optional" // 携带签名时校验，未携带时放行
+	ArgsCheckOff      = "off"      // 不校验
+)
+
+// argsCheckRules 代码中为路由组声明的默认规则
+var argsCheckRules []conf.ArgsCheckRule
+
+// RegisterArgsCheckRule 为路由组或路径声明校验模式，配置文件中的规则优先
+func RegisterArgsCheckRule(pattern, mode string) {
+	argsCheckRules = append(argsCheckRules, conf.ArgsCheckRule{Path: pattern, Mode: mode})
+}
+
+// argsCheckMode 返回请求路径对应的校验模式，未知模式按 required 处理
+func argsCheckMode(p string) string {
+	mode := global.Config.ArgsCheck.Mode
+	if rule, ok := matchArgsCheckRule(global.Config.ArgsCheck.Rules, p); ok {
+		mode = rule.Mode
+	} else if rule, ok = matchArgsCheckRule(argsCheckRules, p); ok {
+		mode = rule.Mode
+	}
+	switch mode {
+	case ArgsCheckOptional, ArgsCheckOff:
+		return mode
+	default:
+		return ArgsCheckRequired
+	}
+}
+
+func matchArgsCheckRule(rules []conf.ArgsCheckRule, p string) (conf.ArgsCheckRule, bool) {
+	for _, rule := range rules {
+		if matchPath(rule.Path, p) {
+			return rule, true
+		}
+	}
+	return conf.ArgsCheckRule{}, false
+}
+
+// matchPath 支持 path.Match 通配符，/** 结尾匹配该路径及其全部子路径
+func matchPath(pattern, p string) bool {
+	if base, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return p == base || strings.HasPrefix(p, base+"/")
+	}
+	ok, _ := path.Match(pattern, p)
+	return ok
+}
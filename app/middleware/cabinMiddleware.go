@@ -1,26 +1,36 @@
 package middleware
 
 import (
+	"gpm/app/service/casbin_service"
 	"gpm/common/res"
-	"gpm/global"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+// CasbinMiddleware 按 主体、租户、api:<路由>、请求方法 鉴权
+// 主体由 JwtMiddleware 写入：用户为 user:<id>，服务账号为 service:<id>
+// 请求属性（用户、IP、时间、资源所有者）供策略条件判定
 func CasbinMiddleware(c *gin.Context) {
 	if c.GetBool("auth") {
 		return
 	}
 	tenant := c.GetHeader("tenant")
-	userId := c.GetString("userId")
-	role := c.GetStringSlice("role")
-	if tenant == "" || userId == "" || role == nil {
+	sub := c.GetString("sub")
+	if tenant == "" || sub == "" {
 		res.FailWithMsg(c, "权限鉴定信息缺失")
 		c.Abort()
 		return
 	}
 
-	ok, err := global.CasbinEnforcer.Enforce()
+	owner, err := resolveOwner(c)
+	if err != nil {
+		res.FailWithError(c, err)
+		c.Abort()
+		return
+	}
+	attrs := casbin_service.NewAttrs(c.GetString("userId"), c.ClientIP(), owner)
+	ok, err := casbin_service.Enforce(c.Request.Context(), sub, tenant, "api:"+c.FullPath(), strings.ToLower(c.Request.Method), attrs)
 	if err != nil {
 		res.FailWithError(c, err)
 		c.Abort()
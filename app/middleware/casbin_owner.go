@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"gpm/global"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// OwnerResolver 解析请求所访问资源的所有者ID，供策略条件 r.attr.Owner 使用
+type OwnerResolver func(c *gin.Context) (string, error)
+
+// ownerResolvers 按路由完整路径注册的所有者解析
+var ownerResolvers = map[string]OwnerResolver{}
+
+// RegisterOwnerResolver 为路由声明资源所有者的解析方式，fullPath 与 c.FullPath() 一致，如 /gpm/doc/:id
+func RegisterOwnerResolver(fullPath string, resolver OwnerResolver) {
+	ownerResolvers[fullPath] = resolver
+}
+
+// OwnerFromParam 按路径参数查询当前租户内模型的 user_id 作为所有者，记录不存在时所有者为空
+// 模型需有 tenant_id 与 user_id 字段，其他租户的同名记录不参与判定
+func OwnerFromParam(m any, param string) OwnerResolver {
+	return func(c *gin.Context) (string, error) {
+		id := c.Param(param)
+		if id == "" {
+			return "", nil
+		}
+		var owner string
+		err := global.DB.WithContext(c.Request.Context()).Model(m).
+			Where("tenant_id = ? AND id = ?", c.GetString("tenant"), id).Select("user_id").Take(&owner).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return owner, err
+	}
+}
+
+// resolveOwner 未注册解析的路由所有者为空
+func resolveOwner(c *gin.Context) (string, error) {
+	resolver, ok := ownerResolvers[c.FullPath()]
+	if !ok {
+		return "", nil
+	}
+	return resolver(c)
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"gpm/app/service/metrics"
+	"gpm/common/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NoImpersonateMiddleware 敏感操作禁止在模拟登录期间执行，需放在 JwtMiddleware 之后
+func NoImpersonateMiddleware(c *gin.Context) {
+	if c.GetString("actorId") == "" {
+		return
+	}
+	metrics.JwtFailures.WithLabelValues("impersonate_denied").Inc()
+	res.FailWithMsgAndCode(c, res.FailAuthCode, "模拟登录期间不允许该操作")
+	c.Abort()
+}
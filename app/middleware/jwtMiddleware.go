@@ -1,16 +1,23 @@
 package middleware
 
 import (
-	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"gpm/app/service/apikey"
+	"gpm/app/service/casbin_service"
 	jwt2 "gpm/app/service/jwt"
+	"gpm/app/service/log"
+	"gpm/app/service/metrics"
+	"gpm/app/service/session"
 	"gpm/common/res"
+	"strings"
 )
 
 func JwtMiddleware(c *gin.Context) {
 	Authorization := c.GetHeader("Authorization")
 	auth := c.GetBool("auth")
 	if Authorization == "" && auth {
+		metrics.JwtFailures.WithLabelValues("missing").Inc()
 		res.FailToken(c)
 		c.Abort()
 		return
@@ -18,13 +25,52 @@ func JwtMiddleware(c *gin.Context) {
 	if Authorization == "" {
 		return
 	}
+	token := strings.TrimPrefix(Authorization, "Bearer ")
+	// 服务账号的 API Key 可直接代替访问令牌
+	if apikey.IsKey(token) {
+		if authApiKey(c, token) {
+			metrics.JwtFailures.WithLabelValues("invalid_api_key").Inc()
+			return
+		}
+		if c.GetString("sub") == "" {
+			metrics.JwtFailures.WithLabelValues("invalid_api_key").Inc()
+			res.FailWithMsgAndCode(c, res.FailTokenCode, "租户级 API Key 不能用作身份令牌")
+			c.Abort()
+		}
+		return
+	}
 	jwt := jwt2.NewJWT()
 
-	accessClaims, err := jwt.ParseAccessToken(Authorization)
+	accessClaims, err := jwt.ParseAccessToken(token)
 	if err != nil {
+		metrics.JwtFailures.WithLabelValues(jwt2.FailReason(err)).Inc()
+		return
+	}
+	if err = session.Check(c.Request.Context(), accessClaims.Sid, accessClaims.Id); err != nil {
+		reason := "revoked"
+		if errors.Is(err, session.ErrUserDisabled) {
+			reason = "disabled"
+		}
+		metrics.JwtFailures.WithLabelValues(reason).Inc()
 		return
 	}
-	ctx := context.WithValue(c.Request.Context(), "userId", accessClaims.Id)
+	if err = session.CheckTenant(c.Request.Context(), accessClaims.Sid, accessClaims.Id, c.GetHeader("tenant")); err != nil {
+		reason := "mfa_required"
+		if errors.Is(err, session.ErrTenantMismatch) {
+			reason = "impersonate_tenant"
+		}
+		metrics.JwtFailures.WithLabelValues(reason).Inc()
+		res.FailWithMsgAndCode(c, res.FailAuthCode, err.Error())
+		c.Abort()
+		return
+	}
+	ctx := log.WithUserId(c.Request.Context(), accessClaims.Id)
 	c.Request = c.Request.WithContext(ctx)
+	c.Set("userId", accessClaims.Id)
+	c.Set("sub", casbin_service.UserSubject(accessClaims.Id))
+	c.Set("sid", accessClaims.Sid)
+	if accessClaims.Act != nil {
+		c.Set("actorId", accessClaims.Act.Sub)
+	}
 	c.Set("user", accessClaims)
 }
@@ -2,29 +2,36 @@ package middleware
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"fmt"
 	"gpm/app/model"
-	"gpm/global"
+	"gpm/app/service/audit"
+	"gpm/app/service/log"
+	"gpm/app/service/metrics"
 	"io"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
 func LogMiddleware(c *gin.Context) {
 	// 记录请求开始的时间
 	startTime := time.Now()
-	logId := uuid.New().String()
+	// 沿用上游传入的 traceparent / X-Request-Id，并回写到响应头
+	traceparent := c.GetHeader(log.HeaderTraceparent)
+	logId := log.ResolveLogId(traceparent, c.GetHeader(log.HeaderRequestId))
+	tenant := c.GetHeader("tenant")
 	c.Set("logId", logId)
-	// 读取请求体（需在 c.Next() 前读取，因为请求体只能读一次）
-	// 使用 context.WithValue 创建一个新的 context，并附加 logId
-	ctx := context.WithValue(c.Request.Context(), "logId", logId)
-	// 将新的 context 替换回请求中
+	c.Set("tenant", tenant)
+	c.Header(log.HeaderRequestId, logId)
+	if _, ok := log.ParseTraceparent(traceparent); ok {
+		c.Header(log.HeaderTraceparent, traceparent)
+	}
+	// 将 logId、租户写入请求 context，供后续日志、GORM 与异步任务使用
+	ctx := log.WithTenant(log.WithLogId(c.Request.Context(), logId), tenant)
 	c.Request = c.Request.WithContext(ctx)
+	// 读取请求体（需在 c.Next() 前读取，因为请求体只能读一次）
 	var requestBody []byte
 	if c.Request.Body != nil {
 		requestBody, _ = c.GetRawData()
@@ -51,7 +58,8 @@ func LogMiddleware(c *gin.Context) {
 	// 构造操作日志结构体
 	actionLog := model.ActionLog{
 		LogID:        logId,
-		UserID:       c.GetString("userID"),
+		UserID:       c.GetString("userId"),
+		ActorID:      c.GetString("actorId"),
 		IP:           c.ClientIP(),
 		UA:           c.Request.UserAgent(),
 		Action:       c.GetString("action"),
@@ -65,14 +73,14 @@ func LogMiddleware(c *gin.Context) {
 		Duration:     fmt.Sprintf("%.5f", duration),
 	}
 	// 写入数据库
-	if err = global.DB.WithContext(c.Request.Context()).Create(&actionLog).Error; err != nil {
-		logrus.WithError(err).WithField("logId", logId).Error("Failed to save action log")
+	if err = audit.AppendActionLog(c.Request.Context(), &actionLog); err != nil {
+		metrics.ActionLogWriteFailures.Inc()
+		log.LogByGin(c, logrus.Fields{"error": err}).Error("Failed to save action log")
 	}
 	duration = time.Since(startTime).Seconds() * 1000
 	// 使用 logrus 输出结构化日志
-	logrus.WithContext(c.Request.Context()).WithFields(logrus.Fields{
+	log.LogByGin(c, logrus.Fields{
 		"type":     "action",
-		"userId":   c.GetInt("action"),
 		"ip":       c.ClientIP(),
 		"method":   c.Request.Method,
 		"path":     c.Request.URL.Path,
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"gpm/app/service/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware 按路由和租户记录请求数与耗时
+// 租户请求头可以随意填写，只有通过签名或 API Key 校验的请求按租户记录，避免标签基数被刷爆
+func MetricsMiddleware(c *gin.Context) {
+	startTime := time.Now()
+	c.Next()
+	route := c.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	tenant := "unknown"
+	if c.GetBool("tenantVerified") {
+		tenant = c.GetHeader("tenant")
+	}
+	metrics.HTTPRequests.WithLabelValues(route, tenant, c.Request.Method, strconv.Itoa(c.Writer.Status())).Inc()
+	metrics.HTTPDuration.WithLabelValues(route, tenant, c.Request.Method).Observe(time.Since(startTime).Seconds())
+}
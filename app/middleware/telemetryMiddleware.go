@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"fmt"
+	"gpm/app/service/telemetry"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TelemetryMiddleware 为每个请求创建服务端 span 并记录 RED 指标，需放在 LogMiddleware 之前
+func TelemetryMiddleware(c *gin.Context) {
+	startTime := time.Now()
+	route := c.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+	ctx, span := telemetry.Tracer().Start(ctx, fmt.Sprintf("%s %s", c.Request.Method, route),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("http.request.method", c.Request.Method),
+			attribute.String("http.route", route),
+			attribute.String("url.path", c.Request.URL.Path),
+			attribute.String("client.address", c.ClientIP()),
+			attribute.String("user_agent.original", c.Request.UserAgent()),
+			attribute.String("gpm.tenant", c.GetHeader("tenant")),
+		),
+	)
+	defer span.End()
+	c.Request = c.Request.WithContext(ctx)
+
+	c.Next()
+
+	status := c.Writer.Status()
+	span.SetAttributes(attribute.Int("http.response.status_code", status))
+	if userId := c.GetString("userId"); userId != "" {
+		span.SetAttributes(attribute.String("enduser.id", userId))
+	}
+	if status >= 500 {
+		span.SetStatus(codes.Error, fmt.Sprintf("status %d", status))
+	}
+	telemetry.RecordRequest(ctx, route, c.Request.Method, status, float64(time.Since(startTime).Microseconds())/1e3)
+}
@@ -2,22 +2,39 @@ package model
 
 type ActionLog struct {
 	BaseModel
-	LogID        string  `gorm:"type:uuid;not null;comment:日志唯一标识" json:"log_id"`
+	LogID        string  `gorm:"type:varchar(128);not null;index;comment:日志唯一标识（沿用上游 traceparent/X-Request-Id）" json:"log_id"`
 	UserID       string  `gorm:"type:uuid;comment:操作用户ID" json:"user_id"`
 	User         User    `gorm:"foreignkey:UserID" json:"-"`
+	ActorID      string  `gorm:"type:varchar(64);default:'';index;comment:模拟登录时的真实操作人ID" json:"actor_id"`
 	IP           string  `gorm:"type:varchar(45);default:'';comment:IP地址" json:"ip"`
 	UA           string  `gorm:"type:varchar(1024);default:'';comment:用户代理" json:"ua"`
 	Action       string  `gorm:"type:varchar(255);default:'';comment:操作描述" json:"action"`
 	Path         string  `gorm:"type:varchar(255);default:'';comment:请求路径" json:"path"`
 	Method       string  `gorm:"type:varchar(10);default:'';comment:请求方法" json:"method"`
-	Tenant       string  `gorm:"type:varchar(255);default:default;comment:所属租户" json:"tenant"`
+	Tenant       string  `gorm:"type:varchar(255);default:default;index:idx_action_log_chain,priority:1;comment:所属租户" json:"tenant"`
 	Header       *string `gorm:"type:text;comment:请求头信息" json:"header,omitempty"`
 	RequestBody  *string `gorm:"type:text;comment:请求体" json:"request_body,omitempty"`
 	ResponseBody *string `gorm:"type:text;comment:响应体" json:"response_body,omitempty"`
 	Status       int     `gorm:"default:0;comment:HTTP状态码" json:"status"`
 	Duration     string  `gorm:"type:varchar(11);default:'0';comment:请求耗时（毫秒）" json:"duration"`
+	Seq          int64   `gorm:"not null;default:0;index:idx_action_log_chain,priority:2;comment:租户内链序号（0=未入链）" json:"seq"`
+	PrevHash     string  `gorm:"type:varchar(64);default:'';comment:上一条日志哈希" json:"prev_hash"`
+	Hash         string  `gorm:"type:varchar(64);default:'';comment:本条日志哈希" json:"hash"`
 }
 
 func (ActionLog) TableName() string {
 	return "action_log"
 }
+
+// ActionLogCheckpoint 操作日志哈希链签名检查点
+type ActionLogCheckpoint struct {
+	BaseModel
+	Tenant    string `gorm:"type:varchar(255);not null;index;comment:所属租户" json:"tenant"`
+	Seq       int64  `gorm:"not null;comment:检查点对应的链序号" json:"seq"`
+	Hash      string `gorm:"type:varchar(64);not null;comment:检查点对应的日志哈希" json:"hash"`
+	Signature string `gorm:"type:varchar(255);not null;comment:检查点签名（base64）" json:"signature"`
+}
+
+func (ActionLogCheckpoint) TableName() string {
+	return "action_log_checkpoint"
+}
@@ -0,0 +1,22 @@
+package model
+
+// ApiKey 租户级或服务账号的 API Key，明文只在签发时返回，库中仅保存哈希
+type ApiKey struct {
+	BaseModel
+	TenantID string `gorm:"type:uuid;not null;index;comment:所属租户标识" json:"tenantId"`
+	Tenant   Tenant `gorm:"foreignkey:TenantID" json:"-"`
+	// ServiceAccountID 为空表示租户级 Key，仅用于替代请求签名
+	ServiceAccountID *string         `gorm:"type:uuid;index;comment:所属服务账号" json:"serviceAccountId"`
+	ServiceAccount   *ServiceAccount `gorm:"foreignkey:ServiceAccountID" json:"-"`
+	Name             string          `gorm:"type:varchar(255);not null;comment:名称" json:"name"`
+	Prefix           string          `gorm:"type:varchar(32);not null;uniqueIndex;comment:公开前缀（用于识别与查找）" json:"prefix"`
+	Hash             string          `gorm:"type:varchar(64);not null;comment:密钥 SHA-256 哈希" json:"-"`
+	Status           bool            `gorm:"not null;default:true;comment:状态 false=已吊销 true=有效" json:"status"`
+	ExpireAt         int             `gorm:"not null;default:0;comment:过期时间（0=永不过期）" json:"expireAt"`
+	AllowIPs         []string        `gorm:"serializer:json;type:text;comment:来源 IP 白名单（IP 或 CIDR，空表示不限制）" json:"allowIps"`
+	LastUsedAt       int             `gorm:"not null;default:0;comment:最近使用时间" json:"lastUsedAt"`
+}
+
+func (ApiKey) TableName() string {
+	return "api_key"
+}
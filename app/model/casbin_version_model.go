@@ -0,0 +1,12 @@
+package model
+
+// CasbinVersion 策略版本号，每次策略变更加一，供多实例判断是否需要重新加载
+type CasbinVersion struct {
+	ID       int   `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	Version  int64 `gorm:"not null;default:0;comment:策略版本号" json:"version"`
+	UpdateAt int   `gorm:"autoUpdateTime;comment:更新时间" json:"update_at"`
+}
+
+func (CasbinVersion) TableName() string {
+	return "casbin_version"
+}
@@ -0,0 +1,42 @@
+package model
+
+// UserMfa 用户 TOTP 配置，验证通过后才启用
+type UserMfa struct {
+	BaseModel
+	UserID      string `gorm:"type:uuid;not null;uniqueIndex;comment:用户标识" json:"userId"`
+	User        User   `gorm:"foreignkey:UserID" json:"-"`
+	Secret      string `gorm:"type:varchar(64);not null;comment:TOTP 密钥（base32）" json:"-"`
+	Enabled     bool   `gorm:"not null;default:false;comment:是否已启用" json:"enabled"`
+	LastStep    int64  `gorm:"not null;default:0;comment:最近一次通过校验的时间步，防止验证码重放" json:"-"`
+	FailedCount int    `gorm:"not null;default:0;comment:连续校验失败次数" json:"-"`
+	LockedUntil int    `gorm:"not null;default:0;comment:校验锁定截止时间（0=未锁定）" json:"lockedUntil"`
+}
+
+func (UserMfa) TableName() string {
+	return "user_mfa"
+}
+
+// MfaRecoveryCode 一次性恢复码，仅保存哈希
+type MfaRecoveryCode struct {
+	BaseModel
+	UserID string `gorm:"type:uuid;not null;index;comment:用户标识" json:"userId"`
+	Hash   string `gorm:"type:varchar(64);not null;comment:恢复码 SHA-256 哈希" json:"-"`
+	UsedAt int    `gorm:"not null;default:0;comment:使用时间（0=未使用）" json:"usedAt"`
+}
+
+func (MfaRecoveryCode) TableName() string {
+	return "mfa_recovery_code"
+}
+
+// MfaPolicy 租户 MFA 策略，拥有指定角色的用户登录时必须通过 MFA
+type MfaPolicy struct {
+	BaseModel
+	TenantID   string   `gorm:"type:uuid;not null;uniqueIndex;comment:所属租户标识" json:"tenantId"`
+	Tenant     Tenant   `gorm:"foreignkey:TenantID" json:"-"`
+	RequireAll bool     `gorm:"not null;default:false;comment:是否要求租户内全部用户" json:"requireAll"`
+	Roles      []string `gorm:"serializer:json;type:text;comment:要求 MFA 的角色 ID 列表" json:"roles"`
+}
+
+func (MfaPolicy) TableName() string {
+	return "mfa_policy"
+}
@@ -0,0 +1,18 @@
+package model
+
+// OAuthClient 接入 gpm 登录的内部应用，按租户注册
+type OAuthClient struct {
+	BaseModel
+	TenantID     string   `gorm:"type:uuid;not null;index;comment:所属租户标识" json:"tenantId"`
+	Tenant       Tenant   `gorm:"foreignkey:TenantID" json:"-"`
+	ClientID     string   `gorm:"type:varchar(64);not null;uniqueIndex;comment:客户端标识" json:"clientId"`
+	SecretHash   string   `gorm:"type:varchar(64);comment:客户端密钥 SHA-256 哈希（公开客户端为空）" json:"-"`
+	Name         string   `gorm:"type:varchar(255);not null;comment:应用名称" json:"name"`
+	RedirectURIs []string `gorm:"serializer:json;type:text;comment:允许的回调地址" json:"redirectUris"`
+	Public       bool     `gorm:"not null;default:false;comment:是否公开客户端（无密钥，必须使用 PKCE）" json:"public"`
+	Status       bool     `gorm:"not null;default:true;comment:状态 false=禁用 true=启用" json:"status"`
+}
+
+func (OAuthClient) TableName() string {
+	return "oauth_client"
+}
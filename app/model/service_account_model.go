@@ -0,0 +1,15 @@
+package model
+
+// ServiceAccount 租户下的服务账号，用于机器间调用，Casbin 主体为 service:<id>
+type ServiceAccount struct {
+	BaseModel
+	TenantID string `gorm:"type:uuid;not null;index;comment:所属租户标识" json:"tenantId"`
+	Tenant   Tenant `gorm:"foreignkey:TenantID" json:"-"`
+	Name     string `gorm:"type:varchar(255);not null;comment:名称" json:"name"`
+	Remark   string `gorm:"type:varchar(255);comment:备注" json:"remark"`
+	Status   bool   `gorm:"not null;default:true;comment:状态 false=禁用 true=启用" json:"status"`
+}
+
+func (ServiceAccount) TableName() string {
+	return "service_account"
+}
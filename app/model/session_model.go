@@ -0,0 +1,21 @@
+package model
+
+// UserSession 登录会话，每次签发令牌对时创建，刷新令牌时更新
+type UserSession struct {
+	BaseModel
+	UserID        string `gorm:"type:uuid;not null;index;comment:用户标识" json:"userId"`
+	User          User   `gorm:"foreignkey:UserID" json:"-"`
+	TenantID      string `gorm:"type:varchar(64);index;comment:登录租户" json:"tenantId"`
+	Device        string `gorm:"type:varchar(128);comment:设备名称" json:"device"`
+	UserAgent     string `gorm:"type:varchar(512);comment:浏览器标识" json:"userAgent"`
+	IP            string `gorm:"type:varchar(64);comment:登录 IP" json:"ip"`
+	ActorID       string `gorm:"type:varchar(64);default:'';comment:模拟登录的真实操作人ID" json:"actorId"`
+	MfaAt         int    `gorm:"not null;default:0;comment:通过多因素认证的时间（0=未通过）" json:"mfaAt"`
+	LastRefreshAt int    `gorm:"not null;default:0;comment:最近刷新时间" json:"lastRefreshAt"`
+	ExpireAt      int    `gorm:"not null;comment:过期时间" json:"expireAt"`
+	RevokedAt     int    `gorm:"not null;default:0;comment:吊销时间（0=有效）" json:"revokedAt"`
+}
+
+func (UserSession) TableName() string {
+	return "user_session"
+}
@@ -0,0 +1,18 @@
+package model
+
+// TenantSignKey 租户请求签名密钥，同一租户可同时存在多个有效密钥以支持轮换
+type TenantSignKey struct {
+	BaseModel
+	TenantID  string `gorm:"type:uuid;not null;index;comment:所属租户标识" json:"tenantId"`
+	Tenant    Tenant `gorm:"foreignkey:TenantID" json:"-"`
+	KeyID     string `gorm:"type:varchar(64);not null;uniqueIndex;comment:密钥标识（随请求头 key-id 传递）" json:"keyId"`
+	Secret    string `gorm:"type:varchar(128);not null;comment:HMAC 密钥" json:"-"`
+	Remark    string `gorm:"type:varchar(255);default:'';comment:备注" json:"remark"`
+	Status    bool   `gorm:"not null;default:true;comment:状态 false=已吊销 true=有效" json:"status"`
+	ExpireAt  int    `gorm:"not null;default:0;comment:过期时间（0=永不过期）" json:"expireAt"`
+	RevokedAt int    `gorm:"not null;default:0;comment:吊销时间" json:"revokedAt"`
+}
+
+func (TenantSignKey) TableName() string {
+	return "tenant_sign_key"
+}
@@ -0,0 +1,15 @@
+package model
+
+// SigningKey 令牌签名密钥，公钥通过 JWKS 公布
+type SigningKey struct {
+	BaseModel
+	Kid        string `gorm:"type:varchar(64);not null;uniqueIndex;comment:密钥标识（JWT 头部 kid）" json:"kid"`
+	Alg        string `gorm:"type:varchar(16);not null;comment:签名算法" json:"alg"`
+	PrivateKey string `gorm:"type:text;not null;comment:私钥（PKCS#8 PEM）" json:"-"`
+	Status     bool   `gorm:"not null;default:true;comment:状态 false=已停用 true=有效" json:"status"`
+	ExpireAt   int    `gorm:"not null;default:0;comment:公钥失效时间（0=永不失效）" json:"expireAt"`
+}
+
+func (SigningKey) TableName() string {
+	return "signing_key"
+}
@@ -0,0 +1,25 @@
+package model
+
+// TemporaryGrant 临时授权，生效后写入 Casbin 策略或角色分配，到期由后台任务回收
+type TemporaryGrant struct {
+	BaseModel
+	TenantID    string `gorm:"type:varchar(64);not null;index;comment:所属租户" json:"tenantId"`
+	Kind        string `gorm:"type:varchar(16);not null;comment:授权类型（role=角色分配，policy=直接授权）" json:"kind"`
+	Subject     string `gorm:"type:varchar(128);not null;index;comment:被授权主体，如 user:<id>" json:"subject"`
+	Role        string `gorm:"type:varchar(128);default:'';comment:分配的角色主体，如 role:<id>" json:"role"`
+	Obj         string `gorm:"type:varchar(255);default:'';comment:授权对象" json:"obj"`
+	Act         string `gorm:"type:varchar(64);default:'';comment:授权操作" json:"act"`
+	Duration    int    `gorm:"not null;comment:授权时长（秒）" json:"duration"`
+	Reason      string `gorm:"type:varchar(512);default:'';comment:申请理由" json:"reason"`
+	Status      string `gorm:"type:varchar(16);not null;index;comment:状态（pending/active/rejected/expired/revoked）" json:"status"`
+	RequesterID string `gorm:"type:varchar(64);not null;comment:申请人ID" json:"requesterId"`
+	ApproverID  string `gorm:"type:varchar(64);default:'';comment:审批人ID" json:"approverId"`
+	Comment     string `gorm:"type:varchar(512);default:'';comment:审批意见" json:"comment"`
+	ApprovedAt  int    `gorm:"not null;default:0;comment:审批时间" json:"approvedAt"`
+	ExpireAt    int    `gorm:"not null;default:0;index;comment:到期时间（0=未生效）" json:"expireAt"`
+	RevokedAt   int    `gorm:"not null;default:0;comment:回收时间" json:"revokedAt"`
+}
+
+func (TemporaryGrant) TableName() string {
+	return "temporary_grant"
+}
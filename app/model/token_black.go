@@ -2,7 +2,7 @@ package model
 
 type TokenBlack struct {
 	BaseModel
-	TokenUuid string
+	TokenUuid string `gorm:"index"`
 	Reason    string `gorm:"type:varchar(255)"`
 	StarTime  int    `gorm:"" json:"starTime"`
 	StopTime  int    `gorm:"" json:"stopTime"`
@@ -0,0 +1,15 @@
+package model
+
+// UserIdentity 外部身份提供方账号与本地用户的关联
+type UserIdentity struct {
+	BaseModel
+	UserID   string `gorm:"type:uuid;not null;index;comment:本地用户标识" json:"userId"`
+	User     User   `gorm:"foreignkey:UserID" json:"-"`
+	Provider string `gorm:"type:varchar(64);not null;uniqueIndex:idx_identity_subject;comment:身份提供方名称" json:"provider"`
+	Subject  string `gorm:"type:varchar(255);not null;uniqueIndex:idx_identity_subject;comment:提供方用户标识（sub）" json:"subject"`
+	Email    string `gorm:"type:varchar(255);comment:提供方返回的邮箱" json:"email"`
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identity"
+}
@@ -0,0 +1,16 @@
+package model
+
+// UserToken 邮箱验证、重置密码等一次性令牌的使用记录
+type UserToken struct {
+	BaseModel
+	Jti      string `gorm:"type:varchar(64);not null;uniqueIndex;comment:令牌标识" json:"jti"`
+	UserID   string `gorm:"type:uuid;not null;index;comment:用户标识" json:"userId"`
+	User     User   `gorm:"foreignkey:UserID" json:"-"`
+	Purpose  string `gorm:"type:varchar(32);not null;comment:用途 verify_email/reset_password/mfa_login" json:"purpose"`
+	ExpireAt int    `gorm:"not null;comment:过期时间" json:"expireAt"`
+	UsedAt   int    `gorm:"not null;default:0;comment:使用时间（0=未使用）" json:"usedAt"`
+}
+
+func (UserToken) TableName() string {
+	return "user_token"
+}
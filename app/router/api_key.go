@@ -0,0 +1,16 @@
+package router
+
+import (
+	"gpm/app/controller"
+	"gpm/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ApiKeyRoute(r *gin.RouterGroup) {
+	app := controller.AdminApi{}.ApiKeyApi
+	apiKeyRoute := r.Group("apiKey")
+	apiKeyRoute.GET("", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.ApiKeyListView)
+	apiKeyRoute.POST("", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.IssueApiKeyView)
+	apiKeyRoute.POST("revoke", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.RevokeApiKeyView)
+}
@@ -0,0 +1,16 @@
+package router
+
+import (
+	"gpm/app/controller"
+	"gpm/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AuditRoute(r *gin.RouterGroup) {
+	app := controller.AdminApi{}.AuditApi
+	auditRoute := r.Group("audit")
+	auditRoute.GET("verify", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.VerifyChainView)
+	auditRoute.GET("checkpoint", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.CheckpointListView)
+	auditRoute.GET("checkpoint/export", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.ExportCheckpointView)
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"gpm/app/controller"
+	"gpm/app/middleware"
+	"gpm/app/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+func DocRoute(r *gin.RouterGroup) {
+	app := controller.AdminApi{}.DocApi
+	docRoute := r.Group("doc")
+	docRoute.GET("", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.DocListView)
+	docRoute.POST("", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.AddDocView)
+	docRoute.PUT(":id", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.UpdateDocView)
+	docRoute.DELETE(":id", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.RemoveDocView)
+	// 按文档创建人解析所有者，策略可用 r.attr.Owner == r.attr.UserId 限定只能操作自己的文档
+	middleware.RegisterOwnerResolver(docRoute.BasePath()+"/:id", middleware.OwnerFromParam(&model.Doc{}, "id"))
+}
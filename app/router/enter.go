@@ -5,17 +5,39 @@ import (
 	"gpm/global"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
 func Run() {
 	engine := gin.Default()
+	// 客户端 IP 用于 API Key 白名单、策略条件与会话记录，只信任配置的代理转发的地址
+	if err := engine.SetTrustedProxies(global.Config.System.TrustedProxies); err != nil {
+		logrus.Fatalf("可信代理配置错误: %s", err)
+	}
+	MetricsRoute(engine)
 	r := engine.Group("gpm")
-	r.Use(middleware.LogMiddleware, middleware.ArgsCheckMiddleware)
+	if global.Config.Metrics.Enable {
+		r.Use(middleware.MetricsMiddleware)
+	}
+	r.Use(middleware.TelemetryMiddleware, middleware.LogMiddleware, middleware.ArgsCheckMiddleware)
+	HealthRoute(r)
 	UserRoute(r)
+	OidcRoute(r)
+	OAuthRoute(r)
+	MfaRoute(r)
+	SessionRoute(r)
+	ImpersonateRoute(r)
+	PermissionRoute(r)
+	GrantRoute(r)
 	SearchRoute(r)
 	ApiRoute(r)
+	DocRoute(r)
+	AuditRoute(r)
+	SignKeyRoute(r)
+	ApiKeyRoute(r)
+	ServiceAccountRoute(r)
 	err := engine.Run(global.Config.System.Addr())
 	if err != nil {
-		return
+		logrus.Fatalf("服务启动失败: %s", err)
 	}
 }
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"gpm/app/controller"
+	"gpm/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GrantRoute(r *gin.RouterGroup) {
+	app := controller.AdminApi{}.GrantApi
+	grantRoute := r.Group("grant")
+	grantRoute.GET("mine", middleware.AuthMiddleware, middleware.JwtMiddleware, app.MyGrantListView)
+	grantRoute.POST("request", middleware.AuthMiddleware, middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, app.RequestGrantView)
+	grantRoute.GET("", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.GrantListView)
+	grantRoute.POST("", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.GrantView)
+	grantRoute.PUT("approve", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.ApproveGrantView)
+	grantRoute.PUT("reject", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.RejectGrantView)
+	grantRoute.DELETE("", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.RevokeGrantView)
+}
@@ -0,0 +1,15 @@
+package router
+
+import (
+	"gpm/app/controller"
+	"gpm/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func HealthRoute(r *gin.RouterGroup) {
+	app := controller.AdminApi{}.HealthApi
+	// 健康检查由负载均衡等探针调用，无需签名
+	middleware.RegisterArgsCheckRule(r.BasePath()+"/health", middleware.ArgsCheckOff)
+	r.GET("health", app.HealthView)
+}
@@ -0,0 +1,15 @@
+package router
+
+import (
+	"gpm/app/controller"
+	"gpm/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ImpersonateRoute(r *gin.RouterGroup) {
+	app := controller.AdminApi{}.SessionApi
+	impersonateRoute := r.Group("impersonate")
+	impersonateRoute.POST("", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.ImpersonateView)
+	impersonateRoute.DELETE("", middleware.AuthMiddleware, middleware.JwtMiddleware, app.EndImpersonateView)
+}
@@ -0,0 +1,21 @@
+package router
+
+import (
+	"gpm/app/service/metrics"
+	"gpm/global"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsRoute 注册 Prometheus 指标接口，不经过签名校验
+func MetricsRoute(engine *gin.Engine) {
+	mc := global.Config.Metrics
+	if !mc.Enable {
+		return
+	}
+	path := mc.Path
+	if path == "" {
+		path = "/metrics"
+	}
+	engine.GET(path, gin.WrapH(metrics.Handler()))
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"gpm/app/controller"
+	"gpm/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MfaRoute(r *gin.RouterGroup) {
+	app := controller.AdminApi{}.MfaApi
+	mfaRoute := r.Group("mfa")
+	// 绑定与启用可使用登录第一步返回的 MFA 令牌，其余接口仅限本人，模拟登录期间均不可用
+	mfaRoute.POST("enroll", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, app.MfaEnrollView)
+	mfaRoute.POST("activate", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, app.MfaActivateView)
+	mfaRoute.POST("disable", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, app.MfaDisableView)
+	mfaRoute.POST("recoveryCodes", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, app.MfaRecoveryCodesView)
+	mfaRoute.GET("policy", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.MfaPolicyView)
+	mfaRoute.PUT("policy", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.UpdateMfaPolicyView)
+}
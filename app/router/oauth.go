@@ -0,0 +1,27 @@
+package router
+
+import (
+	"gpm/app/controller"
+	"gpm/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func OAuthRoute(r *gin.RouterGroup) {
+	app := controller.AdminApi{}.OAuthApi
+	// 标准 OIDC 端点由浏览器和第三方库调用，无法携带签名
+	middleware.RegisterArgsCheckRule(r.BasePath()+"/.well-known/**", middleware.ArgsCheckOff)
+	middleware.RegisterArgsCheckRule(r.BasePath()+"/oauth/**", middleware.ArgsCheckOff)
+	r.GET(".well-known/openid-configuration", app.DiscoveryView)
+	r.GET(".well-known/jwks.json", app.JwksView)
+	oauthRoute := r.Group("oauth")
+	oauthRoute.GET("authorize", app.AuthorizeView)
+	oauthRoute.POST("token", app.TokenView)
+	oauthRoute.GET("userinfo", app.UserinfoView)
+	oauthRoute.POST("userinfo", app.UserinfoView)
+
+	clientRoute := r.Group("oauthClient")
+	clientRoute.GET("", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.ClientListView)
+	clientRoute.POST("", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.AddClientView)
+	clientRoute.DELETE("", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.RemoveClientView)
+}
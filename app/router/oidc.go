@@ -0,0 +1,17 @@
+package router
+
+import (
+	"gpm/app/controller"
+	"gpm/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func OidcRoute(r *gin.RouterGroup) {
+	app := controller.AdminApi{}.OidcApi
+	// 浏览器跳转发起的请求无法携带签名
+	middleware.RegisterArgsCheckRule(r.BasePath()+"/oidc/**", middleware.ArgsCheckOff)
+	oidcRoute := r.Group("oidc")
+	oidcRoute.GET(":provider/login", app.OidcLoginView)
+	oidcRoute.GET(":provider/callback", app.OidcCallbackView)
+}
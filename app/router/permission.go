@@ -0,0 +1,23 @@
+package router
+
+import (
+	"gpm/app/controller"
+	"gpm/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func PermissionRoute(r *gin.RouterGroup) {
+	app := controller.AdminApi{}.PermissionApi
+	permissionRoute := r.Group("permission")
+	permissionRoute.POST("", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.AddPolicyView)
+	permissionRoute.DELETE("", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.RemovePolicyView)
+	permissionRoute.POST("batch", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.BatchAddPolicyView)
+	permissionRoute.DELETE("batch", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.BatchRemovePolicyView)
+	permissionRoute.PUT("replace", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.ReplacePolicyView)
+	permissionRoute.GET("export", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.ExportPolicyView)
+	permissionRoute.POST("import", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.ImportPolicyView)
+	permissionRoute.GET("explain", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.ExplainView)
+	permissionRoute.GET("effective", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.EffectivePolicyView)
+	permissionRoute.GET("preview", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.PolicyPreviewView)
+}
@@ -0,0 +1,17 @@
+package router
+
+import (
+	"gpm/app/controller"
+	"gpm/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ServiceAccountRoute(r *gin.RouterGroup) {
+	app := controller.AdminApi{}.ServiceAccountApi
+	serviceAccountRoute := r.Group("serviceAccount")
+	serviceAccountRoute.GET("", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.ServiceAccountListView)
+	serviceAccountRoute.POST("", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.AddServiceAccountView)
+	serviceAccountRoute.PUT("", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.UpdateServiceAccountView)
+	serviceAccountRoute.DELETE("", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.RemoveServiceAccountView)
+}
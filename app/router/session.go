@@ -0,0 +1,17 @@
+package router
+
+import (
+	"gpm/app/controller"
+	"gpm/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SessionRoute(r *gin.RouterGroup) {
+	app := controller.AdminApi{}.SessionApi
+	sessionRoute := r.Group("session")
+	sessionRoute.GET("", middleware.AuthMiddleware, middleware.JwtMiddleware, app.SessionListView)
+	sessionRoute.DELETE("", middleware.AuthMiddleware, middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, app.RevokeSessionView)
+	sessionRoute.GET("user", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.UserSessionListView)
+	sessionRoute.DELETE("user", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.RevokeUserSessionView)
+}
@@ -0,0 +1,17 @@
+package router
+
+import (
+	"gpm/app/controller"
+	"gpm/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SignKeyRoute(r *gin.RouterGroup) {
+	app := controller.AdminApi{}.SignKeyApi
+	signKeyRoute := r.Group("signKey")
+	signKeyRoute.GET("", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.SignKeyListView)
+	signKeyRoute.POST("", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.IssueSignKeyView)
+	signKeyRoute.POST("rotate", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.RotateSignKeyView)
+	signKeyRoute.POST("revoke", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.RevokeSignKeyView)
+}
@@ -9,6 +9,21 @@ import (
 func UserRoute(r *gin.RouterGroup) {
 	app := controller.AdminApi{}.UserApi
 	userRoute := r.Group("user")
-	userRoute.GET("login", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.UserLoginView)
-	userRoute.POST("register", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.UserRegisterView)
+	userRoute.GET("login", app.UserLoginView)
+	userRoute.POST("login/mfa", app.UserMfaLoginView)
+	userRoute.POST("refresh", app.UserRefreshView)
+	userRoute.POST("register", app.UserRegisterView)
+	userRoute.POST("verify", app.UserVerifyEmailView)
+	userRoute.POST("verify/resend", app.UserResendVerifyView)
+	userRoute.POST("password/forgot", app.UserForgotPasswordView)
+	userRoute.POST("password/reset", app.UserResetPasswordView)
+	userRoute.GET("profile", middleware.AuthMiddleware, middleware.JwtMiddleware, app.UserProfileView)
+	userRoute.PUT("profile", middleware.AuthMiddleware, middleware.JwtMiddleware, app.UpdateProfileView)
+	userRoute.PUT("password", middleware.AuthMiddleware, middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, app.UpdatePasswordView)
+	userRoute.GET("", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.UserListView)
+	userRoute.GET("detail", middleware.JwtMiddleware, middleware.CasbinMiddleware, app.UserDetailView)
+	userRoute.PUT("status", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.UpdateUserStatusView)
+	userRoute.GET("tenant", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.UserTenantListView)
+	userRoute.POST("tenant", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.AddUserTenantView)
+	userRoute.DELETE("tenant", middleware.JwtMiddleware, middleware.NoImpersonateMiddleware, middleware.CasbinMiddleware, app.RemoveUserTenantView)
 }
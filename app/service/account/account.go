@@ -0,0 +1,116 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gpm/app/model"
+	"gpm/app/service/log"
+	"gpm/app/service/mail"
+	"gpm/app/service/session"
+	"gpm/common/util"
+	"gpm/global"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrAlreadyVerified = errors.New("邮箱已验证")
+
+// SendVerifyEmail 发送邮箱验证邮件
+func SendVerifyEmail(ctx context.Context, user *model.User) error {
+	if user.VerifiedAt > 0 {
+		return ErrAlreadyVerified
+	}
+	mc := global.Config.Mail
+	expire := expireOr(mc.VerifyExpire, 24*time.Hour)
+	token, err := issueToken(ctx, user.ID, PurposeVerifyEmail, expire)
+	if err != nil {
+		return err
+	}
+	return mail.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "请验证您的邮箱",
+		Body: fmt.Sprintf("您好，请在 %d 分钟内打开以下链接完成邮箱验证：\n\n%s\n\n如非本人操作请忽略此邮件。",
+			int(expire.Minutes()), link(mc.VerifyURL, token)),
+	})
+}
+
+// VerifyEmail 使用验证令牌完成邮箱验证，不改变管理员设置的启用状态
+func VerifyEmail(ctx context.Context, token string) error {
+	return global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		userID, err := consumeToken(tx, token, PurposeVerifyEmail)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.User{}).Where("id = ?", userID).Update("verified_at", int(time.Now().Unix())).Error
+	})
+}
+
+// ResendVerifyEmail 按邮箱重新发送验证邮件，账号不存在或已验证时静默返回，避免暴露注册情况
+func ResendVerifyEmail(ctx context.Context, email string) error {
+	var user model.User
+	err := global.DB.WithContext(ctx).Where("email = ?", email).Take(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.VerifiedAt > 0 {
+		return nil
+	}
+	return SendVerifyEmail(ctx, &user)
+}
+
+// SendResetEmail 发送重置密码邮件，账号不存在时静默返回
+func SendResetEmail(ctx context.Context, email string) error {
+	var user model.User
+	err := global.DB.WithContext(ctx).Where("email = ?", email).Take(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Ctx(ctx).Infof("重置密码邮箱不存在: %s", email)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	mc := global.Config.Mail
+	expire := expireOr(mc.ResetExpire, 30*time.Minute)
+	token, err := issueToken(ctx, user.ID, PurposeResetPassword, expire)
+	if err != nil {
+		return err
+	}
+	return mail.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "重置密码",
+		Body: fmt.Sprintf("您好，请在 %d 分钟内打开以下链接重置密码：\n\n%s\n\n如非本人操作请忽略此邮件，您的密码不会被修改。",
+			int(expire.Minutes()), link(mc.ResetURL, token)),
+	})
+}
+
+// ResetPassword 使用重置令牌设置新密码，并作废该用户其余未使用的重置令牌
+func ResetPassword(ctx context.Context, token, password string) error {
+	var userID string
+	err := global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		userID, err = consumeToken(tx, token, PurposeResetPassword)
+		if err != nil {
+			return err
+		}
+		salt := uuid.New().String()
+		err = tx.Model(&model.User{}).Where("id = ?", userID).
+			Updates(map[string]any{"password": util.Md5([]byte(password + salt)), "salt": salt}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at = 0", userID, PurposeResetPassword).
+			Update("used_at", int(time.Now().Unix())).Error
+	})
+	if err != nil {
+		return err
+	}
+	// 重置密码后已登录的设备全部下线
+	return session.RevokeAll(ctx, userID, "", session.ReasonPassword)
+}
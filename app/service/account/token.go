@@ -0,0 +1,71 @@
+// package account: 邮箱验证与找回密码
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"gpm/app/model"
+	"gpm/app/service/jwt"
+	"gpm/global"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 一次性令牌用途
+const (
+	PurposeVerifyEmail   = "verify_email"
+	PurposeResetPassword = "reset_password"
+)
+
+// issueToken 签发一次性令牌并记录 jti
+func issueToken(ctx context.Context, userID, purpose string, expire time.Duration) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	jti := hex.EncodeToString(b)
+	err := global.DB.WithContext(ctx).Create(&model.UserToken{
+		Jti:      jti,
+		UserID:   userID,
+		Purpose:  purpose,
+		ExpireAt: int(time.Now().Add(expire).Unix()),
+	}).Error
+	if err != nil {
+		return "", err
+	}
+	return jwt.NewJWT().GenActionToken(userID, purpose, jti, expire)
+}
+
+// consumeToken 校验签名与有效期后标记为已使用，返回用户标识
+func consumeToken(tx *gorm.DB, raw, purpose string) (string, error) {
+	claims, err := jwt.NewJWT().ParseActionToken(raw, purpose)
+	if err != nil {
+		return "", err
+	}
+	result := tx.Model(&model.UserToken{}).
+		Where("jti = ? AND user_id = ? AND purpose = ? AND used_at = 0", claims.ID, claims.Id, purpose).
+		Update("used_at", int(time.Now().Unix()))
+	if result.Error != nil {
+		return "", result.Error
+	}
+	if result.RowsAffected == 0 {
+		return "", jwt.ErrActionTokenInvalid
+	}
+	return claims.Id, nil
+}
+
+func expireOr(seconds int, def time.Duration) time.Duration {
+	if seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return def
+}
+
+func link(base, token string) string {
+	if base == "" {
+		return token
+	}
+	return base + "?token=" + token
+}
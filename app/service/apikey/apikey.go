@@ -0,0 +1,164 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gpm/app/model"
+	"gpm/global"
+	"net"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HeaderApiKey API Key 请求头
+const HeaderApiKey = "X-Api-Key"
+
+// keyScheme 明文格式：gpm_{prefix}_{secret}
+const keyScheme = "gpm"
+
+var (
+	ErrKeyInvalid = errors.New("API Key 无效")
+	ErrKeyRevoked = errors.New("API Key 已吊销")
+	ErrKeyExpired = errors.New("API Key 已过期")
+	ErrIPDenied   = errors.New("来源 IP 不在 API Key 白名单内")
+	ErrAccountOff = errors.New("服务账号已禁用")
+)
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Issue 签发 API Key，返回只可见一次的明文
+func Issue(ctx context.Context, key *model.ApiKey) (string, error) {
+	p := make([]byte, 6)
+	if _, err := rand.Read(p); err != nil {
+		return "", err
+	}
+	s := make([]byte, 32)
+	if _, err := rand.Read(s); err != nil {
+		return "", err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(s)
+	key.Prefix = hex.EncodeToString(p)
+	key.Hash = hashSecret(secret)
+	key.Status = true
+	if err := global.DB.WithContext(ctx).Create(key).Error; err != nil {
+		return "", err
+	}
+	return keyScheme + "_" + key.Prefix + "_" + secret, nil
+}
+
+// parse 拆分明文为前缀与密钥
+func parse(raw string) (prefix, secret string, ok bool) {
+	scheme, rest, found := strings.Cut(raw, "_")
+	if !found || scheme != keyScheme {
+		return "", "", false
+	}
+	prefix, secret, found = strings.Cut(rest, "_")
+	if !found || prefix == "" || secret == "" {
+		return "", "", false
+	}
+	return prefix, secret, true
+}
+
+// IsKey 判断字符串是否为 API Key 明文格式，用于区分 Authorization 中的 JWT
+func IsKey(raw string) bool {
+	_, _, ok := parse(raw)
+	return ok
+}
+
+// Verify 校验 API Key 明文与来源 IP，返回对应记录，服务账号 Key 会一并加载服务账号
+func Verify(ctx context.Context, raw, clientIP string) (*model.ApiKey, error) {
+	prefix, secret, ok := parse(raw)
+	if !ok {
+		return nil, ErrKeyInvalid
+	}
+	var key model.ApiKey
+	err := global.DB.WithContext(ctx).Preload("ServiceAccount").Where("prefix = ?", prefix).Take(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrKeyInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(key.Hash)) != 1 {
+		return nil, ErrKeyInvalid
+	}
+	if !key.Status {
+		return nil, ErrKeyRevoked
+	}
+	now := time.Now().Unix()
+	if key.ExpireAt > 0 && int64(key.ExpireAt) <= now {
+		return nil, ErrKeyExpired
+	}
+	if !allowIP(key.AllowIPs, clientIP) {
+		return nil, ErrIPDenied
+	}
+	if key.ServiceAccount != nil && !key.ServiceAccount.Status {
+		return nil, ErrAccountOff
+	}
+	// 最近使用时间精确到分钟即可，减少写入
+	if now-int64(key.LastUsedAt) > 60 {
+		global.DB.WithContext(ctx).Model(&model.ApiKey{}).Where("id = ?", key.ID).Update("last_used_at", now)
+	}
+	return &key, nil
+}
+
+// allowIP 校验来源 IP，白名单为空时不限制
+func allowIP(allow []string, clientIP string) bool {
+	if len(allow) == 0 {
+		return true
+	}
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return false
+	}
+	for _, item := range allow {
+		if _, ipNet, err := net.ParseCIDR(item); err == nil {
+			if ipNet.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if allowed := net.ParseIP(item); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidAllowIPs 校验白名单条目均为合法的 IP 或 CIDR
+func ValidAllowIPs(allow []string) error {
+	for _, item := range allow {
+		if _, _, err := net.ParseCIDR(item); err == nil {
+			continue
+		}
+		if net.ParseIP(item) == nil {
+			return fmt.Errorf("IP 白名单格式错误: %s", item)
+		}
+	}
+	return nil
+}
+
+// Revoke 吊销 API Key
+func Revoke(ctx context.Context, tenantID, prefix string) error {
+	result := global.DB.WithContext(ctx).Model(&model.ApiKey{}).
+		Where("tenant_id = ? AND prefix = ? AND status = ?", tenantID, prefix, true).
+		Update("status", false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrKeyInvalid
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"gpm/app/model"
+	"gpm/app/service/casbin_service"
+	"gpm/global"
+
+	"gorm.io/gorm"
+)
+
+var ErrAccountNotFound = errors.New("服务账号不存在")
+
+// Subject 返回 Key 对应的 Casbin 主体，租户级 Key 没有主体
+func Subject(key *model.ApiKey) string {
+	if key.ServiceAccountID == nil {
+		return ""
+	}
+	return casbin_service.ServiceSubject(*key.ServiceAccountID)
+}
+
+// GetServiceAccount 查找租户下的服务账号
+func GetServiceAccount(ctx context.Context, tenantID, id string) (*model.ServiceAccount, error) {
+	var account model.ServiceAccount
+	err := global.DB.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).Take(&account).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAccountNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// RemoveServiceAccount 删除服务账号，同时吊销其全部 Key 并清理 Casbin 策略
+func RemoveServiceAccount(ctx context.Context, tenantID, id string) error {
+	err := global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.ApiKey{}).Where("tenant_id = ? AND service_account_id = ?", tenantID, id).Update("status", false).Error
+		if err != nil {
+			return err
+		}
+		result := tx.Where("tenant_id = ? AND id = ?", tenantID, id).Delete(&model.ServiceAccount{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAccountNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = global.CasbinEnforcer.DeleteUser(casbin_service.ServiceSubject(id))
+	return err
+}
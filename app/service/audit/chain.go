@@ -0,0 +1,187 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"gpm/app/model"
+	"gpm/global"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultTenant 与 ActionLog.Tenant 的数据库默认值保持一致
+const DefaultTenant = "default"
+
+// chainPayload 参与哈希计算的规范化内容，字段顺序固定，不可随意调整
+type chainPayload struct {
+	Seq          int64  `json:"seq"`
+	PrevHash     string `json:"prevHash"`
+	LogID        string `json:"logId"`
+	UserID       string `json:"userId"`
+	IP           string `json:"ip"`
+	UA           string `json:"ua"`
+	Action       string `json:"action"`
+	Path         string `json:"path"`
+	Method       string `json:"method"`
+	Tenant       string `json:"tenant"`
+	Header       string `json:"header"`
+	RequestBody  string `json:"requestBody"`
+	ResponseBody string `json:"responseBody"`
+	Status       int    `json:"status"`
+	Duration     string `json:"duration"`
+	CreateAt     int    `json:"createAt"`
+	ActorID      string `json:"actorId,omitempty"` // 后续新增字段，为空时不参与序列化，保证历史哈希不变
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// ComputeHash 计算一条操作日志的链哈希
+func ComputeHash(l *model.ActionLog) (string, error) {
+	byteData, err := json.Marshal(chainPayload{
+		Seq:          l.Seq,
+		PrevHash:     l.PrevHash,
+		LogID:        l.LogID,
+		UserID:       l.UserID,
+		IP:           l.IP,
+		UA:           l.UA,
+		Action:       l.Action,
+		Path:         l.Path,
+		Method:       l.Method,
+		Tenant:       l.Tenant,
+		Header:       deref(l.Header),
+		RequestBody:  deref(l.RequestBody),
+		ResponseBody: deref(l.ResponseBody),
+		Status:       l.Status,
+		Duration:     l.Duration,
+		CreateAt:     l.CreateAt,
+		ActorID:      l.ActorID,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(byteData)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// AppendActionLog 将操作日志追加到所属租户的哈希链末尾并写入数据库
+// 写入前先补齐带列默认值的字段，零值字段会被 GORM 省略而落库为默认值，导致哈希与库中记录不一致
+func AppendActionLog(ctx context.Context, l *model.ActionLog) error {
+	if l.Tenant == "" {
+		l.Tenant = DefaultTenant
+	}
+	if l.Duration == "" {
+		l.Duration = "0"
+	}
+	if l.CreateAt == 0 {
+		l.CreateAt = int(time.Now().Unix())
+	}
+	return global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 同一租户串行写入，避免并发产生分叉
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "action_log:"+l.Tenant).Error; err != nil {
+			return err
+		}
+		var last model.ActionLog
+		err := tx.Select("seq", "hash").
+			Where("tenant = ? AND seq > 0", l.Tenant).
+			Order("seq desc").
+			Take(&last).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		l.Seq = last.Seq + 1
+		l.PrevHash = last.Hash
+		l.Hash, err = ComputeHash(l)
+		if err != nil {
+			return err
+		}
+		return tx.Create(l).Error
+	})
+}
+
+// BrokenLink 哈希链中第一处断裂的位置
+type BrokenLink struct {
+	Tenant string `json:"tenant"`
+	Seq    int64  `json:"seq"`
+	LogID  string `json:"logId"`
+	Reason string `json:"reason"`
+}
+
+// VerifyResult 哈希链校验结果
+type VerifyResult struct {
+	Tenant  string      `json:"tenant"`
+	Count   int64       `json:"count"`   // 已校验的条数
+	Head    string      `json:"head"`    // 校验通过部分的最后一个哈希
+	HeadSeq int64       `json:"headSeq"` // 校验通过部分的最后一个序号
+	Broken  *BrokenLink `json:"broken"`  // 为空表示整条链完好
+}
+
+// Tenants 返回已有哈希链的租户列表
+func Tenants(ctx context.Context) (list []string, err error) {
+	err = global.DB.WithContext(ctx).Model(&model.ActionLog{}).
+		Where("seq > 0").
+		Distinct("tenant").
+		Order("tenant").
+		Pluck("tenant", &list).Error
+	return
+}
+
+// Verify 按序号遍历租户的哈希链，返回第一处断裂
+func Verify(ctx context.Context, tenant string) (*VerifyResult, error) {
+	result := &VerifyResult{Tenant: tenant}
+	for {
+		var batch []model.ActionLog
+		err := global.DB.WithContext(ctx).
+			Where("tenant = ? AND seq > ?", tenant, result.HeadSeq).
+			Order("seq asc").
+			Limit(verifyBatchSize).
+			Find(&batch).Error
+		if err != nil {
+			return nil, err
+		}
+		for i := range batch {
+			l := &batch[i]
+			if reason := checkLink(l, result.HeadSeq, result.Head); reason != "" {
+				result.Broken = &BrokenLink{Tenant: tenant, Seq: l.Seq, LogID: l.LogID, Reason: reason}
+				return result, nil
+			}
+			result.Count++
+			result.HeadSeq = l.Seq
+			result.Head = l.Hash
+		}
+		if len(batch) < verifyBatchSize {
+			break
+		}
+	}
+	if err := verifyLatestCheckpoint(ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+const verifyBatchSize = 500
+
+func checkLink(l *model.ActionLog, prevSeq int64, prevHash string) string {
+	if l.Seq != prevSeq+1 {
+		return "序号不连续，可能存在删除或插入"
+	}
+	if l.PrevHash != prevHash {
+		return "前序哈希不匹配"
+	}
+	hash, err := ComputeHash(l)
+	if err != nil {
+		return err.Error()
+	}
+	if hash != l.Hash {
+		return "内容哈希不匹配，日志可能被修改"
+	}
+	return ""
+}
@@ -0,0 +1,185 @@
+package audit
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"gpm/app/model"
+	"gpm/app/service/log"
+	"gpm/global"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrCheckpointKeyMissing = errors.New("未配置检查点签名密钥")
+
+// signingKey 从配置解析检查点签名私钥
+func signingKey() (ed25519.PrivateKey, error) {
+	if global.Config.Audit.CheckpointKey == "" {
+		return nil, ErrCheckpointKeyMissing
+	}
+	seed, err := base64.StdEncoding.DecodeString(global.Config.Audit.CheckpointKey)
+	if err != nil {
+		return nil, fmt.Errorf("检查点签名密钥解析失败: %w", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("检查点签名密钥长度应为%d字节", ed25519.SeedSize)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// PublicKey 返回检查点验签公钥（base64）
+func PublicKey() (string, error) {
+	key, err := signingKey()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)), nil
+}
+
+// checkpointMessage 检查点的签名原文
+func checkpointMessage(cp *model.ActionLogCheckpoint) []byte {
+	return []byte(fmt.Sprintf("%s\n%d\n%s\n%d", cp.Tenant, cp.Seq, cp.Hash, cp.CreateAt))
+}
+
+// VerifyCheckpoint 校验检查点签名
+func VerifyCheckpoint(cp *model.ActionLogCheckpoint) (bool, error) {
+	key, err := signingKey()
+	if err != nil {
+		return false, err
+	}
+	signature, err := base64.StdEncoding.DecodeString(cp.Signature)
+	if err != nil {
+		return false, nil
+	}
+	return ed25519.Verify(key.Public().(ed25519.PublicKey), checkpointMessage(cp), signature), nil
+}
+
+// CreateCheckpoint 为租户当前链头生成签名检查点，链头未变化时返回 nil
+func CreateCheckpoint(ctx context.Context, tenant string) (*model.ActionLogCheckpoint, error) {
+	key, err := signingKey()
+	if err != nil {
+		return nil, err
+	}
+	db := global.DB.WithContext(ctx)
+	var head model.ActionLog
+	err = db.Select("seq", "hash").
+		Where("tenant = ? AND seq > 0", tenant).
+		Order("seq desc").
+		Take(&head).Error
+	if err != nil {
+		return nil, err
+	}
+	var last model.ActionLogCheckpoint
+	err = db.Where("tenant = ?", tenant).Order("seq desc").Take(&last).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if last.Seq == head.Seq {
+		return nil, nil
+	}
+	cp := model.ActionLogCheckpoint{
+		Tenant: tenant,
+		Seq:    head.Seq,
+		Hash:   head.Hash,
+	}
+	cp.CreateAt = int(time.Now().Unix())
+	cp.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, checkpointMessage(&cp)))
+	if err = db.Create(&cp).Error; err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// CreateCheckpoints 为所有租户生成检查点
+func CreateCheckpoints(ctx context.Context) error {
+	tenants, err := Tenants(ctx)
+	if err != nil {
+		return err
+	}
+	for _, tenant := range tenants {
+		if _, err = CreateCheckpoint(ctx, tenant); err != nil {
+			return fmt.Errorf("租户%s生成检查点失败: %w", tenant, err)
+		}
+	}
+	return nil
+}
+
+// RunCheckpointTicker 按固定间隔生成检查点，直到 ctx 结束
+func RunCheckpointTicker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := CreateCheckpoints(ctx); err != nil {
+				log.Ctx(ctx).Errorf("操作日志检查点生成失败: %s", err)
+			}
+		}
+	}
+}
+
+// CheckpointExport 检查点导出格式，附带验签公钥
+type CheckpointExport struct {
+	PublicKey   string                      `json:"publicKey"`
+	Algorithm   string                      `json:"algorithm"`
+	Checkpoints []model.ActionLogCheckpoint `json:"checkpoints"`
+}
+
+// ExportCheckpoints 导出检查点，tenant 为空时导出全部租户
+func ExportCheckpoints(ctx context.Context, tenant string) (*CheckpointExport, error) {
+	publicKey, err := PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	query := global.DB.WithContext(ctx).Order("tenant, seq")
+	if tenant != "" {
+		query = query.Where("tenant = ?", tenant)
+	}
+	export := &CheckpointExport{PublicKey: publicKey, Algorithm: "ed25519"}
+	if err = query.Find(&export.Checkpoints).Error; err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// verifyLatestCheckpoint 用最近的检查点校验链是否被截断或替换
+func verifyLatestCheckpoint(ctx context.Context, result *VerifyResult) error {
+	var cp model.ActionLogCheckpoint
+	err := global.DB.WithContext(ctx).Where("tenant = ?", result.Tenant).Order("seq desc").Take(&cp).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ok, err := VerifyCheckpoint(&cp)
+	if errors.Is(err, ErrCheckpointKeyMissing) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		result.Broken = &BrokenLink{Tenant: result.Tenant, Seq: cp.Seq, Reason: "检查点签名无效"}
+		return nil
+	}
+	if cp.Seq > result.HeadSeq {
+		result.Broken = &BrokenLink{Tenant: result.Tenant, Seq: result.HeadSeq + 1, Reason: "检查点之前的日志缺失，链可能被截断"}
+		return nil
+	}
+	var l model.ActionLog
+	err = global.DB.WithContext(ctx).Select("log_id", "hash").Where("tenant = ? AND seq = ?", result.Tenant, cp.Seq).Take(&l).Error
+	if err != nil {
+		return err
+	}
+	if l.Hash != cp.Hash {
+		result.Broken = &BrokenLink{Tenant: result.Tenant, Seq: cp.Seq, LogID: l.LogID, Reason: "与签名检查点的哈希不一致"}
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+// package bundle: 租户权限配置的导入导出，引用均使用名称而非 UUID，便于在不同环境间迁移
+package bundle
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gpm/app/service/casbin_service"
+	"io"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Version 当前配置包格式版本
+const Version = 1
+
+// 导出格式
+const (
+	FormatYAML = "yaml"
+	FormatCSV  = "csv"
+)
+
+// Bundle 租户权限配置包
+// 主体写作 user:<用户名>、role:<角色名>、service:<服务账号名>
+// 对象写作 menu:<菜单名>、api:<接口名>，以 / 开头的 api 路径及其他对象原样保留
+// api:<接口名> 导入时转换为该接口的路由路径，与 CasbinMiddleware 判定的对象一致
+type Bundle struct {
+	Version   int        `yaml:"version" json:"version"`
+	Tenant    string     `yaml:"tenant" json:"tenant"` // 租户名称，仅作说明，导入时以目标租户为准
+	Roles     []string   `yaml:"roles" json:"roles"`
+	Menus     []Menu     `yaml:"menus" json:"menus"`
+	Apis      []Api      `yaml:"apis" json:"apis"`
+	Groupings []Grouping `yaml:"groupings" json:"groupings"`
+	Policies  []Policy   `yaml:"policies" json:"policies"`
+
+	// partial 为 true 时（如 CSV）只管理角色分配与授权，角色只增不删
+	partial bool
+}
+
+type Menu struct {
+	Name       string `yaml:"name" json:"name"`
+	RouterPath string `yaml:"routerPath,omitempty" json:"routerPath"`
+	Method     string `yaml:"method,omitempty" json:"method"`
+	Auth       bool   `yaml:"auth,omitempty" json:"auth"`
+	Icon       string `yaml:"icon,omitempty" json:"icon"`
+	Status     bool   `yaml:"status,omitempty" json:"status"`
+	Parent     string `yaml:"parent,omitempty" json:"parent"` // 父级菜单名称
+	Sort       int    `yaml:"sort,omitempty" json:"sort"`
+}
+
+type Api struct {
+	Name   string `yaml:"name" json:"name"`
+	Path   string `yaml:"path,omitempty" json:"path"`
+	Method string `yaml:"method,omitempty" json:"method"`
+	Auth   bool   `yaml:"auth,omitempty" json:"auth"`
+	Status bool   `yaml:"status,omitempty" json:"status"`
+	Menu   string `yaml:"menu,omitempty" json:"menu"` // 所属菜单名称
+}
+
+// Grouping 角色分配 g = sub, role, dom
+type Grouping struct {
+	Sub  string `yaml:"sub" json:"sub"`
+	Role string `yaml:"role" json:"role"`
+}
+
+// Policy 授权 p = sub, dom, obj, act, cond, eft，无条件授权省略 cond，allow 省略 eft
+type Policy struct {
+	Sub  string `yaml:"sub" json:"sub"`
+	Obj  string `yaml:"obj" json:"obj"`
+	Act  string `yaml:"act" json:"act"`
+	Cond string `yaml:"cond,omitempty" json:"cond,omitempty"`
+	Eft  string `yaml:"eft,omitempty" json:"eft,omitempty"`
+}
+
+func (g Grouping) String() string {
+	return g.Sub + ", " + g.Role
+}
+
+func (p Policy) String() string {
+	s := p.Sub + ", " + p.Obj + ", " + p.Act
+	if p.Cond != "" {
+		s += ", " + p.Cond
+	}
+	if p.Eft != "" {
+		s += " (" + p.Eft + ")"
+	}
+	return s
+}
+
+// rule 转换为 Casbin 策略，引用仍为名称
+func (p Policy) rule(dom string) []string {
+	return casbin_service.NewPolicy(p.Sub, dom, p.Obj, p.Act, p.Cond, p.Eft)
+}
+
+// condition 统一无条件授权的表示，配置包中为空，casbin_rule 中为 true
+func condition(cond string) string {
+	cond = strings.TrimSpace(cond)
+	if cond == casbin_service.DefaultCondition {
+		return ""
+	}
+	return cond
+}
+
+// effect 统一允许的表示，配置包中为空，casbin_rule 中为 allow
+func effect(eft string) string {
+	if eft == casbin_service.EffectAllow {
+		return ""
+	}
+	return eft
+}
+
+// Encode 按格式序列化，CSV 仅包含 p、g 规则
+func Encode(b *Bundle, format string) ([]byte, error) {
+	switch format {
+	case "", FormatYAML:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(b); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), enc.Close()
+	case FormatCSV:
+		return encodeCSV(b)
+	default:
+		return nil, fmt.Errorf("不支持的格式: %s", format)
+	}
+}
+
+// Decode 按格式解析，未出现的段落（如缺少 menus）导入时不做处理
+// CSV 中引用的角色会自动补入角色列表
+func Decode(data []byte, format string) (*Bundle, error) {
+	var b *Bundle
+	var err error
+	switch format {
+	case "", FormatYAML:
+		b = &Bundle{}
+		err = yaml.Unmarshal(data, b)
+	case FormatCSV:
+		b, err = decodeCSV(data)
+	default:
+		return nil, fmt.Errorf("不支持的格式: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if b.Version > Version {
+		return nil, fmt.Errorf("配置包版本 %d 高于当前支持的版本 %d", b.Version, Version)
+	}
+	return b, b.validate()
+}
+
+// FormatOf 按文件扩展名推断格式
+func FormatOf(file string) string {
+	if strings.HasSuffix(strings.ToLower(file), ".csv") {
+		return FormatCSV
+	}
+	return FormatYAML
+}
+
+func (b *Bundle) validate() error {
+	seen := map[string]bool{}
+	for _, name := range b.Roles {
+		if name == "" || seen["role:"+name] {
+			return fmt.Errorf("角色名称为空或重复: %q", name)
+		}
+		seen["role:"+name] = true
+	}
+	for _, m := range b.Menus {
+		if m.Name == "" || seen["menu:"+m.Name] {
+			return fmt.Errorf("菜单名称为空或重复: %q", m.Name)
+		}
+		seen["menu:"+m.Name] = true
+	}
+	for _, a := range b.Apis {
+		if a.Name == "" || seen["api:"+a.Name] {
+			return fmt.Errorf("接口名称为空或重复: %q", a.Name)
+		}
+		seen["api:"+a.Name] = true
+	}
+	for _, g := range b.Groupings {
+		if g.Sub == "" || g.Role == "" {
+			return fmt.Errorf("角色分配不完整: %s", g)
+		}
+	}
+	for _, p := range b.Policies {
+		if p.Sub == "" || p.Obj == "" || p.Act == "" {
+			return fmt.Errorf("授权不完整: %s", p)
+		}
+		rule := p.rule(b.Tenant)
+		// api:<接口名> 导入时才转换为路由路径，转换后再校验对象
+		if name, ok := strings.CutPrefix(p.Obj, "api:"); ok && !strings.HasPrefix(name, "/") {
+			rule[2] = "api:/"
+		}
+		if err := casbin_service.ValidatePolicy(rule); err != nil {
+			return fmt.Errorf("授权 %s: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// sort 按名称排序，保证导出结果稳定，便于在 git 中比对
+func (b *Bundle) sort() {
+	slices.Sort(b.Roles)
+	slices.SortFunc(b.Menus, func(x, y Menu) int { return strings.Compare(x.Name, y.Name) })
+	slices.SortFunc(b.Apis, func(x, y Api) int { return strings.Compare(x.Name, y.Name) })
+	slices.SortFunc(b.Groupings, func(x, y Grouping) int { return strings.Compare(x.String(), y.String()) })
+	slices.SortFunc(b.Policies, func(x, y Policy) int { return strings.Compare(x.String(), y.String()) })
+}
+
+// encodeCSV 输出 Casbin 策略文件格式，租户列为租户名称
+func encodeCSV(b *Bundle) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	for _, p := range b.Policies {
+		if err := w.Write(append([]string{"p"}, p.rule(b.Tenant)...)); err != nil {
+			return nil, err
+		}
+	}
+	for _, g := range b.Groupings {
+		if err := w.Write([]string{"g", g.Sub, g.Role, b.Tenant}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func decodeCSV(data []byte) (*Bundle, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+	r.Comment = '#'
+	b := &Bundle{Version: Version, partial: true}
+	roles := map[string]bool{}
+	addRole := func(sub string) {
+		if name, ok := strings.CutPrefix(sub, "role:"); ok && !roles[name] {
+			roles[name] = true
+			b.Roles = append(b.Roles, name)
+		}
+	}
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case len(record) >= 5 && record[0] == "p":
+			b.Tenant = record[2]
+			p := Policy{Sub: record[1], Obj: record[3], Act: record[4]}
+			if len(record) >= 6 {
+				p.Cond = condition(record[5])
+			}
+			if len(record) >= 7 {
+				p.Eft = effect(record[6])
+			}
+			b.Policies = append(b.Policies, p)
+			addRole(record[1])
+		case len(record) >= 4 && record[0] == "g":
+			b.Tenant = record[3]
+			b.Groupings = append(b.Groupings, Grouping{Sub: record[1], Role: record[2]})
+			addRole(record[1])
+			addRole(record[2])
+		default:
+			return nil, fmt.Errorf("无法识别的策略行: %s", strings.Join(record, ", "))
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,191 @@
+package bundle
+
+import (
+	"context"
+	"gpm/app/model"
+	"gpm/app/service/grant"
+	"gpm/global"
+	"slices"
+	"strings"
+
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"gorm.io/gorm"
+)
+
+// Export 导出租户当前的权限配置
+func Export(ctx context.Context, tenantID string) (*Bundle, error) {
+	s, err := load(global.DB.WithContext(ctx), tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.bundle(), nil
+}
+
+// snapshot 租户当前数据及名称与标识的双向映射
+type snapshot struct {
+	tenant   model.Tenant
+	roles    []model.Role
+	menus    []model.Menu
+	apis     []model.Api
+	rules    []gormadapter.CasbinRule
+	names    map[string]string // 标识引用 -> 名称引用，如 role:<id> -> role:<name>
+	ids      map[string]string // 名称引用 -> 标识引用
+	paths    map[string]string // 接口名称 -> 路由路径
+	raw      map[string]bool   // 规则中出现的标识引用
+	warnings []string
+}
+
+func load(db *gorm.DB, tenantID string) (*snapshot, error) {
+	s := &snapshot{names: map[string]string{}, ids: map[string]string{}, paths: map[string]string{}, raw: map[string]bool{}}
+	if err := db.Where("id = ?", tenantID).Take(&s.tenant).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("tenant_id = ?", tenantID).Order("name").Find(&s.roles).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("tenant_id = ?", tenantID).Order("name").Find(&s.menus).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("tenant_id = ?", tenantID).Order("name").Find(&s.apis).Error; err != nil {
+		return nil, err
+	}
+	err := db.Where("(ptype = ? AND v1 = ?) OR (ptype = ? AND v2 = ?)", "p", tenantID, "g", tenantID).
+		Order("id").Find(&s.rules).Error
+	if err != nil {
+		return nil, err
+	}
+	if err = s.excludeTemporary(db); err != nil {
+		return nil, err
+	}
+	for _, r := range s.rules {
+		s.raw[r.V0] = true
+		if r.Ptype == "g" {
+			s.raw[r.V1] = true
+		}
+	}
+	var services []model.ServiceAccount
+	if err = db.Where("tenant_id = ?", tenantID).Find(&services).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range s.roles {
+		s.link("role", r.ID, r.Name)
+	}
+	for _, m := range s.menus {
+		s.link("menu", m.ID, m.Name)
+	}
+	for _, a := range s.apis {
+		s.link("api", a.ID, a.Name)
+		s.paths[a.Name] = a.Path
+	}
+	for _, sa := range services {
+		s.link("service", sa.ID, sa.Name)
+	}
+	return s, s.loadUsers(db)
+}
+
+// excludeTemporary 临时授权到期自动回收，不属于配置包管理的数据，导出时不包含，prune 时也不删除
+func (s *snapshot) excludeTemporary(db *gorm.DB) error {
+	temporary, err := grant.Rules(db, s.tenant.ID)
+	if err != nil || len(temporary) == 0 {
+		return err
+	}
+	skip := make(map[string]bool, len(temporary))
+	for _, r := range temporary {
+		skip[ruleKey(r)] = true
+	}
+	s.rules = slices.DeleteFunc(s.rules, func(r gormadapter.CasbinRule) bool { return skip[ruleKey(r)] })
+	return nil
+}
+
+// loadUsers 用户是全局的，只加载规则中出现的用户
+func (s *snapshot) loadUsers(db *gorm.DB) error {
+	var ids []string
+	for _, r := range s.rules {
+		if id, ok := strings.CutPrefix(r.V0, "user:"); ok {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	var users []model.User
+	if err := db.Select("id", "username").Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return err
+	}
+	for _, u := range users {
+		s.link("user", u.ID, u.Username)
+	}
+	return nil
+}
+
+func (s *snapshot) link(kind, id, name string) {
+	if _, ok := s.ids[kind+":"+name]; ok {
+		s.warnings = append(s.warnings, "名称重复，按标识引用: "+kind+":"+name)
+		return
+	}
+	s.names[kind+":"+id] = kind + ":" + name
+	s.ids[kind+":"+name] = kind + ":" + id
+}
+
+// name 将标识引用转换为名称引用，无法转换时原样返回
+func (s *snapshot) name(ref string) string {
+	if name, ok := s.names[ref]; ok {
+		return name
+	}
+	return ref
+}
+
+func (s *snapshot) bundle() *Bundle {
+	b := &Bundle{
+		Version:   Version,
+		Tenant:    s.tenant.Name,
+		Roles:     []string{},
+		Menus:     []Menu{},
+		Apis:      []Api{},
+		Groupings: []Grouping{},
+		Policies:  []Policy{},
+	}
+	for _, r := range s.roles {
+		b.Roles = append(b.Roles, r.Name)
+	}
+	for _, m := range s.menus {
+		b.Menus = append(b.Menus, Menu{
+			Name:       m.Name,
+			RouterPath: m.RouterPath,
+			Method:     m.Method,
+			Auth:       m.Auth,
+			Icon:       m.Icon,
+			Status:     m.Status,
+			Parent:     s.refName("menu", m.ParentID),
+			Sort:       m.Sort,
+		})
+	}
+	for _, a := range s.apis {
+		b.Apis = append(b.Apis, Api{
+			Name:   a.Name,
+			Path:   a.Path,
+			Method: a.Method,
+			Auth:   a.Auth,
+			Status: a.Status,
+			Menu:   s.refName("menu", a.MenuID),
+		})
+	}
+	for _, r := range s.rules {
+		switch r.Ptype {
+		case "p":
+			b.Policies = append(b.Policies, Policy{Sub: s.name(r.V0), Obj: s.name(r.V2), Act: r.V3, Cond: condition(r.V4), Eft: effect(r.V5)})
+		case "g":
+			b.Groupings = append(b.Groupings, Grouping{Sub: s.name(r.V0), Role: s.name(r.V1)})
+		}
+	}
+	b.sort()
+	return b
+}
+
+// refName 返回外键对应的名称（不含前缀）
+func (s *snapshot) refName(kind, id string) string {
+	if id == "" {
+		return ""
+	}
+	return strings.TrimPrefix(s.name(kind+":"+id), kind+":")
+}
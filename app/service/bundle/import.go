@@ -0,0 +1,446 @@
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gpm/app/model"
+	"gpm/app/service/casbin_service"
+	"gpm/app/service/watcher"
+	"gpm/global"
+	"strings"
+
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errDryRun 预演结束后回滚事务
+var errDryRun = errors.New("dry run")
+
+// Options 导入选项
+type Options struct {
+	Apply bool // false 时只计算差异，不写入
+	Prune bool // 删除配置包中不存在的角色、菜单、接口与规则
+}
+
+// Section 某一段落的变更，元素为名称或规则文本
+type Section struct {
+	Create []string `json:"create"`
+	Update []string `json:"update"`
+	Delete []string `json:"delete"`
+}
+
+// Diff 配置包与数据库的差异，未开启 Prune 时 Delete 仅作提示
+type Diff struct {
+	Roles     Section  `json:"roles"`
+	Menus     Section  `json:"menus"`
+	Apis      Section  `json:"apis"`
+	Groupings Section  `json:"groupings"`
+	Policies  Section  `json:"policies"`
+	Warnings  []string `json:"warnings"`
+	Applied   bool     `json:"applied"`
+}
+
+// Import 比对配置包与租户当前配置，Apply 时在同一事务中写入，成功后重新加载 Casbin 策略
+func Import(ctx context.Context, tenantID string, b *Bundle, opt Options) (*Diff, error) {
+	var diff *Diff
+	err := global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "policy_bundle:"+tenantID).Error; err != nil {
+			return err
+		}
+		s, err := load(tx, tenantID)
+		if err != nil {
+			return err
+		}
+		diff = compare(s.bundle(), b, opt)
+		diff.Warnings = append(diff.Warnings, s.warnings...)
+		// 预演同样走一遍写入流程，以便提前发现无法解析的引用，最后回滚
+		if err = apply(tx, s, b, opt); err != nil {
+			return err
+		}
+		if !opt.Apply {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		return diff, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	diff.Applied = true
+	if global.CasbinEnforcer != nil {
+		if err = global.CasbinEnforcer.LoadPolicy(); err != nil {
+			return nil, err
+		}
+	}
+	// 直接写入了 casbin_rule，需通知其他实例
+	if err = watcher.Notify(ctx); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// compare 在名称空间中比对，结果与配置包内容一一对应
+func compare(cur, want *Bundle, opt Options) *Diff {
+	d := &Diff{Warnings: []string{}}
+	for _, s := range []*Section{&d.Roles, &d.Menus, &d.Apis, &d.Groupings, &d.Policies} {
+		*s = Section{Create: []string{}, Update: []string{}, Delete: []string{}}
+	}
+	if want.Roles != nil {
+		d.Roles = compareSet(cur.Roles, want.Roles, func(s string) string { return s }, nil)
+		if want.partial {
+			d.Roles.Delete = []string{}
+		}
+	}
+	if want.Menus != nil && !want.partial {
+		d.Menus = compareSet(cur.Menus, want.Menus, func(m Menu) string { return m.Name }, func(x, y Menu) bool { return x == y })
+	}
+	if want.Apis != nil && !want.partial {
+		d.Apis = compareSet(cur.Apis, want.Apis, func(a Api) string { return a.Name }, func(x, y Api) bool { return x == y })
+	}
+	if want.Groupings != nil {
+		d.Groupings = compareSet(cur.Groupings, want.Groupings, Grouping.String, nil)
+	}
+	if want.Policies != nil {
+		d.Policies = compareSet(cur.Policies, want.Policies, Policy.String, nil)
+	}
+	if !opt.Prune {
+		count := 0
+		for _, s := range []*Section{&d.Roles, &d.Menus, &d.Apis, &d.Groupings, &d.Policies} {
+			count += len(s.Delete)
+		}
+		if count > 0 {
+			d.Warnings = append(d.Warnings, fmt.Sprintf("未开启 prune，保留 %d 项配置包中不存在的数据", count))
+		}
+	}
+	return d
+}
+
+func compareSet[T any](cur, want []T, key func(T) string, equal func(x, y T) bool) Section {
+	sec := Section{Create: []string{}, Update: []string{}, Delete: []string{}}
+	current := make(map[string]T, len(cur))
+	for _, item := range cur {
+		current[key(item)] = item
+	}
+	wanted := make(map[string]bool, len(want))
+	for _, item := range want {
+		k := key(item)
+		wanted[k] = true
+		old, ok := current[k]
+		switch {
+		case !ok:
+			sec.Create = append(sec.Create, k)
+		case equal != nil && !equal(old, item):
+			sec.Update = append(sec.Update, k)
+		}
+	}
+	for _, item := range cur {
+		if k := key(item); !wanted[k] {
+			sec.Delete = append(sec.Delete, k)
+		}
+	}
+	return sec
+}
+
+// apply 依次写入角色、菜单、接口，再将规则中的名称解析为标识后同步 casbin_rule
+func apply(tx *gorm.DB, s *snapshot, b *Bundle, opt Options) error {
+	tenantID := s.tenant.ID
+	prune := opt.Prune && !b.partial
+	if b.Roles != nil {
+		if err := applyRoles(tx, s, b.Roles, prune); err != nil {
+			return err
+		}
+	}
+	if b.Menus != nil && !b.partial {
+		if err := applyMenus(tx, s, b.Menus, prune); err != nil {
+			return err
+		}
+	}
+	if b.Apis != nil && !b.partial {
+		if err := applyApis(tx, s, b.Apis, prune); err != nil {
+			return err
+		}
+	}
+	if err := resolveUsers(tx, s, b); err != nil {
+		return err
+	}
+	var want []gormadapter.CasbinRule
+	if b.Groupings != nil {
+		for _, g := range b.Groupings {
+			sub, err := s.id(g.Sub)
+			if err != nil {
+				return err
+			}
+			role, err := s.id(g.Role)
+			if err != nil {
+				return err
+			}
+			want = append(want, gormadapter.CasbinRule{Ptype: "g", V0: sub, V1: role, V2: tenantID})
+		}
+	}
+	if b.Policies != nil {
+		for _, p := range b.Policies {
+			sub, err := s.id(p.Sub)
+			if err != nil {
+				return err
+			}
+			obj, err := s.objID(p.Obj)
+			if err != nil {
+				return err
+			}
+			if err = casbin_service.ValidateObject(obj); err != nil {
+				return err
+			}
+			rule := casbin_service.NewPolicy(sub, tenantID, obj, p.Act, p.Cond, p.Eft)
+			want = append(want, gormadapter.CasbinRule{Ptype: "p", V0: rule[0], V1: rule[1], V2: rule[2], V3: rule[3], V4: rule[4], V5: rule[5]})
+		}
+	}
+	return applyRules(tx, s.rules, want, b.Groupings != nil, b.Policies != nil, opt.Prune)
+}
+
+func applyRoles(tx *gorm.DB, s *snapshot, names []string, prune bool) error {
+	wanted := map[string]bool{}
+	for _, name := range names {
+		wanted[name] = true
+		if _, ok := s.ids["role:"+name]; ok {
+			continue
+		}
+		role := model.Role{Name: name, TenantId: s.tenant.ID}
+		if err := tx.Create(&role).Error; err != nil {
+			return err
+		}
+		s.link("role", role.ID, role.Name)
+	}
+	if !prune {
+		return nil
+	}
+	for _, r := range s.roles {
+		if !wanted[r.Name] {
+			if err := tx.Delete(&r).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func applyMenus(tx *gorm.DB, s *snapshot, menus []Menu, prune bool) error {
+	current := map[string]model.Menu{}
+	for _, m := range s.menus {
+		current[m.Name] = m
+	}
+	// 先创建缺失的菜单，再统一更新字段，父级菜单可能在配置包中排在后面
+	for _, m := range menus {
+		if _, ok := current[m.Name]; ok {
+			continue
+		}
+		row := model.Menu{Name: m.Name, TenantID: s.tenant.ID}
+		if err := tx.Omit("ParentID").Create(&row).Error; err != nil {
+			return err
+		}
+		current[m.Name] = row
+		s.link("menu", row.ID, row.Name)
+	}
+	for _, m := range menus {
+		parent, err := s.refID("menu", m.Parent)
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&model.Menu{}).Where("id = ?", current[m.Name].ID).Updates(map[string]any{
+			"router_path": m.RouterPath,
+			"method":      m.Method,
+			"auth":        m.Auth,
+			"icon":        m.Icon,
+			"status":      m.Status,
+			"parent_id":   parent,
+			"sort":        m.Sort,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+	if !prune {
+		return nil
+	}
+	wanted := map[string]bool{}
+	for _, m := range menus {
+		wanted[m.Name] = true
+	}
+	for _, m := range s.menus {
+		if !wanted[m.Name] {
+			if err := tx.Delete(&m).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func applyApis(tx *gorm.DB, s *snapshot, apis []Api, prune bool) error {
+	current := map[string]model.Api{}
+	for _, a := range s.apis {
+		current[a.Name] = a
+	}
+	for _, a := range apis {
+		menu, err := s.refID("menu", a.Menu)
+		if err != nil {
+			return err
+		}
+		values := map[string]any{
+			"path":    a.Path,
+			"method":  a.Method,
+			"auth":    a.Auth,
+			"status":  a.Status,
+			"menu_id": menu,
+		}
+		row, ok := current[a.Name]
+		if !ok {
+			row = model.Api{Name: a.Name, TenantID: s.tenant.ID}
+			if err = tx.Omit("MenuID").Create(&row).Error; err != nil {
+				return err
+			}
+			s.link("api", row.ID, row.Name)
+		}
+		s.paths[a.Name] = a.Path
+		if err = tx.Model(&model.Api{}).Where("id = ?", row.ID).Updates(values).Error; err != nil {
+			return err
+		}
+	}
+	if !prune {
+		return nil
+	}
+	wanted := map[string]bool{}
+	for _, a := range apis {
+		wanted[a.Name] = true
+	}
+	for _, a := range s.apis {
+		if !wanted[a.Name] {
+			if err := tx.Delete(&a).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ruleKey 规则比对使用的唯一键
+func ruleKey(r gormadapter.CasbinRule) string {
+	return strings.Join([]string{r.Ptype, r.V0, r.V1, r.V2, r.V3, r.V4, r.V5}, "\x00")
+}
+
+// applyRules 同步 casbin_rule，只处理配置包中出现的规则类型
+func applyRules(tx *gorm.DB, current, want []gormadapter.CasbinRule, groupings, policies, prune bool) error {
+	managed := func(r gormadapter.CasbinRule) bool {
+		return (r.Ptype == "g" && groupings) || (r.Ptype == "p" && policies)
+	}
+	exists := map[string]bool{}
+	for _, r := range current {
+		exists[ruleKey(r)] = true
+	}
+	wanted := map[string]bool{}
+	var create []gormadapter.CasbinRule
+	for _, r := range want {
+		k := ruleKey(r)
+		if wanted[k] {
+			continue
+		}
+		wanted[k] = true
+		if !exists[k] {
+			create = append(create, r)
+		}
+	}
+	if prune {
+		var ids []uint
+		for _, r := range current {
+			if managed(r) && !wanted[ruleKey(r)] {
+				ids = append(ids, r.ID)
+			}
+		}
+		if len(ids) > 0 {
+			if err := tx.Where("id IN ?", ids).Delete(&gormadapter.CasbinRule{}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	if len(create) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&create).Error
+}
+
+// resolveUsers 加载配置包中引用但当前规则中未出现的用户
+func resolveUsers(tx *gorm.DB, s *snapshot, b *Bundle) error {
+	var names []string
+	for _, g := range b.Groupings {
+		if name, ok := strings.CutPrefix(g.Sub, "user:"); ok {
+			if _, known := s.ids[g.Sub]; !known {
+				names = append(names, name)
+			}
+		}
+	}
+	for _, p := range b.Policies {
+		if name, ok := strings.CutPrefix(p.Sub, "user:"); ok {
+			if _, known := s.ids[p.Sub]; !known {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	var users []model.User
+	if err := tx.Select("id", "username").Where("username IN ?", names).Find(&users).Error; err != nil {
+		return err
+	}
+	for _, u := range users {
+		s.link("user", u.ID, u.Username)
+	}
+	return nil
+}
+
+// id 将名称引用解析为标识引用
+func (s *snapshot) id(ref string) (string, error) {
+	if id, ok := s.ids[ref]; ok {
+		return id, nil
+	}
+	// 导出时无法转换的标识引用（如已删除的角色）原样保留
+	if s.raw[ref] {
+		return ref, nil
+	}
+	return "", fmt.Errorf("无法解析引用: %s", ref)
+}
+
+// objID 解析授权对象，以 / 开头的接口路径及菜单、接口以外的对象原样保留
+// 接口名称转换为路由路径，旧数据中的 api:<id> 经导出后同样按名称转换
+func (s *snapshot) objID(ref string) (string, error) {
+	kind, value, _ := strings.Cut(ref, ":")
+	if (kind != "menu" && kind != "api") || strings.HasPrefix(value, "/") {
+		return ref, nil
+	}
+	if kind == "api" {
+		path, ok := s.paths[value]
+		if !ok {
+			return "", fmt.Errorf("无法解析引用: %s", ref)
+		}
+		if !strings.HasPrefix(path, "/") {
+			return "", fmt.Errorf("接口未配置路由路径: %s", ref)
+		}
+		return "api:" + path, nil
+	}
+	return s.id(ref)
+}
+
+// refID 解析外键名称，空名称返回 nil
+func (s *snapshot) refID(kind, name string) (any, error) {
+	if name == "" {
+		return nil, nil
+	}
+	id, err := s.id(kind + ":" + name)
+	if err != nil {
+		return nil, err
+	}
+	return strings.TrimPrefix(id, kind+":"), nil
+}
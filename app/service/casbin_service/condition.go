@@ -0,0 +1,89 @@
+package casbin_service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/util"
+	"github.com/casbin/govaluate"
+)
+
+// DefaultCondition 无条件授权，casbin_rule 会去掉末尾的空值，因此不能用空字符串
+const DefaultCondition = "true"
+
+// Attrs 请求属性，策略条件中以 r.attr.<字段> 引用，例如：
+//
+//	r.attr.Hour >= 9 && r.attr.Hour < 18 && r.attr.Weekday >= 1 && r.attr.Weekday <= 5
+//	ipMatch(r.attr.Ip, '10.0.0.0/8')
+//	r.attr.Owner == r.attr.UserId
+type Attrs struct {
+	UserId  string // 当前用户ID，服务账号为空
+	Ip      string // 客户端 IP
+	Time    int64  // 请求时间（Unix 秒）
+	Hour    int    // 请求时间的小时 0-23（服务器时区）
+	Weekday int    // 星期 0-6，0 为周日
+	Owner   string // 资源所有者ID，路由未注册所有者解析时为空
+}
+
+// NewAttrs 按当前时间构造请求属性
+func NewAttrs(userId, ip, owner string) Attrs {
+	return NewAttrsAt(userId, ip, owner, time.Now())
+}
+
+// NewAttrsAt 按指定时间构造请求属性，用于权限说明中模拟其他时间点
+func NewAttrsAt(userId, ip, owner string, now time.Time) Attrs {
+	return Attrs{
+		UserId:  userId,
+		Ip:      ip,
+		Time:    now.Unix(),
+		Hour:    now.Hour(),
+		Weekday: int(now.Weekday()),
+		Owner:   owner,
+	}
+}
+
+// NewPolicy 构造策略 [sub, dom, obj, act, cond, eft]，cond 为空时视为无条件，eft 为空时为 allow
+func NewPolicy(sub, dom, obj, act, cond, eft string) []string {
+	if eft == "" {
+		eft = EffectAllow
+	}
+	return []string{sub, dom, obj, act, Condition(cond), eft}
+}
+
+// Condition 规范化条件表达式，空表达式返回 DefaultCondition
+func Condition(cond string) string {
+	cond = strings.TrimSpace(cond)
+	if cond == "" {
+		return DefaultCondition
+	}
+	return cond
+}
+
+// ValidateCondition 校验条件表达式：可解析、只引用 r.attr，且结果为布尔值
+func ValidateCondition(cond string) error {
+	cond = strings.TrimSpace(cond)
+	if cond == "" || cond == DefaultCondition {
+		return nil
+	}
+	fm := model.LoadFunctionMap()
+	functions := fm.GetFunctions()
+	expression, err := govaluate.NewEvaluableExpressionWithFunctions(util.EscapeAssertion(cond), functions)
+	if err != nil {
+		return fmt.Errorf("条件表达式不合法: %w", err)
+	}
+	for _, v := range expression.Vars() {
+		if v != "r_attr" {
+			return fmt.Errorf("条件表达式只能引用 r.attr: %s", strings.Replace(v, "_", ".", 1))
+		}
+	}
+	result, err := expression.Evaluate(map[string]interface{}{"r_attr": NewAttrs("", "127.0.0.1", "")})
+	if err != nil {
+		return fmt.Errorf("条件表达式求值失败: %w", err)
+	}
+	if _, ok := result.(bool); !ok {
+		return fmt.Errorf("条件表达式结果必须为布尔值")
+	}
+	return nil
+}
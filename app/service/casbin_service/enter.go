@@ -1 +1,54 @@
 package casbin_service
+
+import (
+	"context"
+	"fmt"
+	"gpm/app/service/metrics"
+	"gpm/app/service/telemetry"
+	"gpm/global"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Enforce 带链路追踪的权限判定
+func Enforce(ctx context.Context, rvals ...interface{}) (bool, error) {
+	request := make([]string, 0, len(rvals))
+	for _, v := range rvals {
+		request = append(request, fmt.Sprint(v))
+	}
+	_, span := telemetry.Tracer().Start(ctx, "casbin.Enforce", trace.WithAttributes(
+		attribute.StringSlice("casbin.request", request),
+	))
+	defer span.End()
+	if !hasPolicy() {
+		// 匹配器含 eval()，策略为空时 casbin 会报错，此时直接拒绝
+		metrics.CasbinDecisions.WithLabelValues("deny").Inc()
+		span.SetAttributes(attribute.Bool("casbin.allowed", false))
+		return false, nil
+	}
+	ok, err := global.CasbinEnforcer.Enforce(rvals...)
+	if err != nil {
+		metrics.CasbinDecisions.WithLabelValues("error").Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return false, err
+	}
+	if ok {
+		metrics.CasbinDecisions.WithLabelValues("allow").Inc()
+	} else {
+		metrics.CasbinDecisions.WithLabelValues("deny").Inc()
+	}
+	span.SetAttributes(attribute.Bool("casbin.allowed", ok))
+	return ok, nil
+}
+
+// hasPolicy 是否存在任意授权策略
+func hasPolicy() bool {
+	lock := global.CasbinEnforcer.GetLock()
+	lock.RLock()
+	defer lock.RUnlock()
+	ast, ok := global.CasbinEnforcer.GetModel()["p"]["p"]
+	return ok && len(ast.Policy) > 0
+}
@@ -0,0 +1,161 @@
+package casbin_service
+
+import (
+	"gpm/common/util/casbin_util"
+	"gpm/global"
+)
+
+// 缺失授权的补全方式
+const (
+	MissingRole   = "role"   // 分配一个已拥有该权限的角色
+	MissingPolicy = "policy" // 直接为主体添加授权
+)
+
+// Subject 解码后的主体，如 role:<id> 解码为 Type=role、Id=<id>
+type Subject struct {
+	Subject string `json:"subject"`
+	Type    string `json:"type"`
+	Id      string `json:"id"`
+}
+
+// Explanation 权限判定说明
+type Explanation struct {
+	Allowed bool          `json:"allowed"`
+	Request []string      `json:"request"`           // sub, dom, obj, act
+	Attrs   Attrs         `json:"attrs"`             // 参与条件判定的请求属性
+	Policy  []string      `json:"policy,omitempty"`  // 命中的策略，被 deny 策略拒绝时为该 deny 策略
+	Chain   []Subject     `json:"chain,omitempty"`   // 从请求主体到策略主体的角色继承链
+	Missing *MissingGrant `json:"missing,omitempty"` // 未命中任何策略时最近的缺失授权
+}
+
+// MissingGrant 补全后即可通过判定的最小授权
+type MissingGrant struct {
+	Kind    string   `json:"kind"`
+	Rule    []string `json:"rule"` // role 为 g 规则 [sub, role, dom]，policy 为 p 规则 [sub, dom, obj, act, cond, eft]
+	Subject Subject  `json:"subject"`
+}
+
+// Permission 主体在租户下的有效权限
+type Permission struct {
+	Obj    string    `json:"obj"`
+	Act    string    `json:"act"`
+	Cond   string    `json:"cond"` // 生效条件，true 表示无条件
+	Eft    string    `json:"eft"`  // allow 或 deny
+	Policy []string  `json:"policy"`
+	Chain  []Subject `json:"chain"` // 权限来源的角色继承链
+}
+
+// DecodeSubject 解码主体字符串
+func DecodeSubject(s string) Subject {
+	sub := casbin_util.NewSub().DecodeStr(s)
+	return Subject{Subject: s, Type: sub.Type, Id: sub.Id}
+}
+
+// Explain 判定并说明主体能否在租户内对对象执行操作，attrs 为条件判定使用的请求属性
+func Explain(sub, dom, obj, act string, attrs Attrs) (*Explanation, error) {
+	var ok bool
+	var policy []string
+	if hasPolicy() {
+		var err error
+		ok, policy, err = global.CasbinEnforcer.EnforceEx(sub, dom, obj, act, attrs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	result := &Explanation{Allowed: ok, Request: []string{sub, dom, obj, act}, Attrs: attrs}
+	order, parents, err := roleGraph(sub, dom)
+	if err != nil {
+		return nil, err
+	}
+	if len(policy) > 0 {
+		result.Policy = policy
+		result.Chain = chain(parents, policy[0])
+	}
+	if ok || len(policy) > 0 {
+		// 被 deny 策略拒绝时补充授权无效
+		return result, nil
+	}
+	result.Missing, err = nearestMissing(sub, dom, obj, act, order)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Permissions 主体在租户下直接或经角色继承获得的全部权限
+func Permissions(sub, dom string) ([]Permission, error) {
+	order, parents, err := roleGraph(sub, dom)
+	if err != nil {
+		return nil, err
+	}
+	list := []Permission{}
+	for _, node := range order {
+		policies, err := global.CasbinEnforcer.GetFilteredPolicy(0, node, dom)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range policies {
+			if len(p) < 6 {
+				continue
+			}
+			list = append(list, Permission{Obj: p[2], Act: p[3], Cond: p[4], Eft: p[5], Policy: p, Chain: chain(parents, node)})
+		}
+	}
+	return list, nil
+}
+
+// roleGraph 广度优先展开主体在租户内继承的角色，order 按距离由近到远
+func roleGraph(sub, dom string) (order []string, parents map[string]string, err error) {
+	parents = map[string]string{sub: ""}
+	order = []string{sub}
+	for i := 0; i < len(order); i++ {
+		rules, err := global.CasbinEnforcer.GetFilteredGroupingPolicy(0, order[i], "", dom)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, rule := range rules {
+			if _, seen := parents[rule[1]]; seen {
+				continue
+			}
+			parents[rule[1]] = order[i]
+			order = append(order, rule[1])
+		}
+	}
+	return order, parents, nil
+}
+
+// chain 回溯从请求主体到 target 的继承链，target 不在图中时返回空
+func chain(parents map[string]string, target string) []Subject {
+	if _, ok := parents[target]; !ok {
+		return nil
+	}
+	var list []Subject
+	for node := target; node != ""; node = parents[node] {
+		list = append([]Subject{DecodeSubject(node)}, list...)
+	}
+	return list
+}
+
+// nearestMissing 优先推荐租户内已拥有该权限的角色（含通配授权），否则建议直接授权
+// 条件不满足导致的拒绝同样给出建议，条件本身需要人工判断
+func nearestMissing(sub, dom, obj, act string, order []string) (*MissingGrant, error) {
+	policies, err := global.CasbinEnforcer.GetFilteredPolicy(1, dom)
+	if err != nil {
+		return nil, err
+	}
+	held := make(map[string]bool, len(order))
+	for _, node := range order {
+		held[node] = true
+	}
+	for _, p := range policies {
+		if len(p) < 6 || p[5] != EffectAllow || !ObjMatch(obj, p[2]) || !ActMatch(act, p[3]) {
+			continue
+		}
+		role := DecodeSubject(p[0])
+		if role.Type != SubjectTypeRole || held[p[0]] {
+			continue
+		}
+		return &MissingGrant{Kind: MissingRole, Rule: []string{sub, p[0], dom}, Subject: role}, nil
+	}
+	return &MissingGrant{Kind: MissingPolicy, Rule: NewPolicy(sub, dom, obj, act, "", EffectAllow), Subject: DecodeSubject(sub)}, nil
+}
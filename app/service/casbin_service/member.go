@@ -0,0 +1,90 @@
+package casbin_service
+
+import (
+	"gpm/global"
+	"slices"
+	"strings"
+)
+
+// IsMember 用户在租户下是否有角色分配，成员关系以 Casbin 为准
+func IsMember(userId, tenant string) bool {
+	if tenant == "" {
+		return false
+	}
+	rules, err := global.CasbinEnforcer.GetFilteredGroupingPolicy(0, UserSubject(userId), "", tenant)
+	return err == nil && len(rules) > 0
+}
+
+// MemberIds 租户下全部成员的用户ID
+func MemberIds(tenant string) ([]string, error) {
+	// SyncedEnforcer 未封装该方法，需自行加读锁
+	lock := global.CasbinEnforcer.GetLock()
+	lock.RLock()
+	subjects, err := global.CasbinEnforcer.GetAllUsersByDomain(tenant)
+	lock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, sub := range subjects {
+		if id, ok := strings.CutPrefix(sub, SubjectTypeUser+":"); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// Covers target 在租户内继承的角色与直接允许的授权是否都包含在 actor 的权限中
+// 用于限制模拟登录只能模拟权限不高于自己的用户
+func Covers(actor, target, dom string) (bool, error) {
+	actorRoles, _, err := roleGraph(actor, dom)
+	if err != nil {
+		return false, err
+	}
+	held := make(map[string]bool, len(actorRoles))
+	for _, role := range actorRoles[1:] {
+		held[role] = true
+	}
+	targetRoles, _, err := roleGraph(target, dom)
+	if err != nil {
+		return false, err
+	}
+	for _, role := range targetRoles[1:] {
+		if !held[role] {
+			return false, nil
+		}
+	}
+	policies, err := global.CasbinEnforcer.GetFilteredPolicy(0, target, dom)
+	if err != nil {
+		return false, err
+	}
+	if len(policies) == 0 {
+		return true, nil
+	}
+	permissions, err := Permissions(actor, dom)
+	if err != nil {
+		return false, err
+	}
+	granted := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		granted[ruleKey(p.Policy[2:])] = true
+	}
+	for _, p := range policies {
+		if len(p) < 6 || p[5] != EffectAllow {
+			continue
+		}
+		if !granted[ruleKey(p[2:])] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Inherits sub 是否就是 target，或在租户内经角色继承获得 target 的权限
+func Inherits(sub, target, dom string) (bool, error) {
+	order, _, err := roleGraph(sub, dom)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(order, target), nil
+}
@@ -0,0 +1,117 @@
+package casbin_service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/casbin/casbin/v2/util"
+)
+
+// 策略效果，deny 优先于 allow
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// AnyAction 匹配全部操作
+const AnyAction = "*"
+
+// Actions 策略可用的操作，多个操作以 | 连接组成操作集合，如 get|post
+var Actions = []string{"get", "post", "put", "delete", "read", "write", "owen"}
+
+// ObjMatch 判断对象是否匹配策略对象，与匹配器中的 objMatch 一致
+// 策略对象以 * 结尾时按前缀匹配，否则精确匹配；以 /* 结尾时同时匹配模块根路由，
+// 如 api:/gpm/doc/* 匹配 /gpm/doc 下的全部接口以及 /gpm/doc 本身（列表、新增等接口）
+func ObjMatch(obj, pattern string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && obj == prefix {
+		return true
+	}
+	return util.KeyMatch(obj, pattern)
+}
+
+// ObjMatchFunc 注册到匹配器的 objMatch(r.obj, p.obj)
+func ObjMatchFunc(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return false, errors.New("objMatch 需要 2 个参数")
+	}
+	obj, ok1 := args[0].(string)
+	pattern, ok2 := args[1].(string)
+	if !ok1 || !ok2 {
+		return false, errors.New("objMatch 参数必须为字符串")
+	}
+	return ObjMatch(obj, pattern), nil
+}
+
+// ActMatch 判断操作是否属于策略的操作集合
+func ActMatch(act, pattern string) bool {
+	if pattern == AnyAction {
+		return true
+	}
+	return slices.Contains(strings.Split(pattern, "|"), act)
+}
+
+// ActMatchFunc 注册到匹配器的 actMatch(r.act, p.act)
+func ActMatchFunc(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return false, errors.New("actMatch 需要 2 个参数")
+	}
+	act, ok1 := args[0].(string)
+	pattern, ok2 := args[1].(string)
+	if !ok1 || !ok2 {
+		return false, errors.New("actMatch 参数必须为字符串")
+	}
+	return ActMatch(act, pattern), nil
+}
+
+// ValidatePolicy 校验策略 [sub, dom, obj, act, cond, eft] 的对象模式、操作集合、条件与效果
+func ValidatePolicy(rule []string) error {
+	if len(rule) != 6 {
+		return fmt.Errorf("策略长度不正确: %v", rule)
+	}
+	if err := ValidateObject(rule[2]); err != nil {
+		return err
+	}
+	if err := ValidateAction(rule[3]); err != nil {
+		return err
+	}
+	if err := ValidateCondition(rule[4]); err != nil {
+		return err
+	}
+	if rule[5] != EffectAllow && rule[5] != EffectDeny {
+		return fmt.Errorf("未知的策略效果: %s", rule[5])
+	}
+	return nil
+}
+
+// ValidateObject 接口对象必须是路由路径（与 CasbinMiddleware 判定的 api:<FullPath> 一致），通配符只能用于接口对象，且只能出现在末尾
+func ValidateObject(obj string) error {
+	if path, ok := strings.CutPrefix(obj, "api:"); ok && !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("接口对象必须为路由路径，如 api:/gpm/user: %s", obj)
+	}
+	i := strings.Index(obj, "*")
+	if i == -1 {
+		return nil
+	}
+	if !strings.HasPrefix(obj, "api:") {
+		return fmt.Errorf("只有接口对象支持通配符: %s", obj)
+	}
+	if i != len(obj)-1 {
+		return fmt.Errorf("通配符只能出现在末尾: %s", obj)
+	}
+	return nil
+}
+
+// ValidateAction 操作为 * 或以 | 连接的已知操作
+func ValidateAction(act string) error {
+	if act == AnyAction {
+		return nil
+	}
+	for _, a := range strings.Split(act, "|") {
+		if !slices.Contains(Actions, a) {
+			return fmt.Errorf("未知的操作: %s", a)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package casbin_service
+
+import (
+	"context"
+	"errors"
+	"gpm/app/service/watcher"
+	"gpm/global"
+	"slices"
+	"strings"
+
+	"github.com/casbin/casbin/v2"
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// AddPolicies 批量添加策略，已存在的策略跳过，返回实际添加的策略
+func AddPolicies(rules [][]string) ([][]string, error) {
+	if err := validateRules(rules); err != nil {
+		return nil, err
+	}
+	added := missingPolicies(global.CasbinEnforcer, rules)
+	if len(added) == 0 {
+		return added, nil
+	}
+	if _, err := global.CasbinEnforcer.AddPoliciesEx(added); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// RemovePolicies 批量移除策略，不存在的策略跳过，返回实际移除的策略
+// 规则中为空的字段视为通配，如省略条件时移除该授权的全部条件版本
+func RemovePolicies(rules [][]string) ([][]string, error) {
+	removed := existingPolicies(global.CasbinEnforcer, rules)
+	if len(removed) == 0 {
+		return removed, nil
+	}
+	if _, err := global.CasbinEnforcer.RemovePolicies(removed); err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// ReplacePolicies 将主体在租户内的策略整体替换为 rules，与现有策略比对后在同一事务中增删
+func ReplacePolicies(sub, dom string, rules [][]string) (added, removed [][]string, err error) {
+	for _, rule := range rules {
+		if len(rule) < 2 || rule[0] != sub || rule[1] != dom {
+			return nil, nil, errors.New("策略主体或租户与替换目标不一致")
+		}
+	}
+	if err = validateRules(rules); err != nil {
+		return nil, nil, err
+	}
+	err = transaction(func(e casbin.IEnforcer) error {
+		current, err := e.GetFilteredPolicy(0, sub, dom)
+		if err != nil {
+			return err
+		}
+		want := dedupe(rules)
+		removed = diff(current, want)
+		added = diff(want, current)
+		if len(removed) > 0 {
+			if _, err = e.RemovePolicies(removed); err != nil {
+				return err
+			}
+		}
+		if len(added) > 0 {
+			if _, err = e.AddPolicies(added); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return added, removed, nil
+}
+
+// transaction 在数据库事务中执行策略变更，失败时回滚并重新加载策略
+func transaction(fc func(casbin.IEnforcer) error) error {
+	adapter, ok := global.CasbinEnforcer.GetAdapter().(*gormadapter.Adapter)
+	if !ok {
+		return errors.New("当前 Casbin 适配器不支持事务")
+	}
+	err := adapter.Transaction(global.CasbinEnforcer, fc)
+	if err != nil {
+		// 事务内的变更已逐条广播，回滚后通知其他实例全量加载
+		if notifyErr := watcher.Notify(context.Background()); notifyErr != nil {
+			logrus.Warnf("策略回滚通知失败: %s", notifyErr)
+		}
+	}
+	return err
+}
+
+func missingPolicies(e casbin.IEnforcer, rules [][]string) [][]string {
+	list := [][]string{}
+	for _, rule := range dedupe(rules) {
+		if ok, _ := e.HasPolicy(rule); !ok {
+			list = append(list, rule)
+		}
+	}
+	return list
+}
+
+func existingPolicies(e casbin.IEnforcer, rules [][]string) [][]string {
+	list := [][]string{}
+	for _, rule := range dedupe(rules) {
+		policies, _ := e.GetFilteredPolicy(0, rule...)
+		list = append(list, policies...)
+	}
+	return dedupe(list)
+}
+
+// validateRules 校验待写入的策略
+func validateRules(rules [][]string) error {
+	for _, rule := range rules {
+		if err := ValidatePolicy(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diff 返回 a 中存在而 b 中不存在的规则
+func diff(a, b [][]string) [][]string {
+	set := make(map[string]bool, len(b))
+	for _, rule := range b {
+		set[ruleKey(rule)] = true
+	}
+	list := [][]string{}
+	for _, rule := range a {
+		if !set[ruleKey(rule)] {
+			list = append(list, rule)
+		}
+	}
+	return list
+}
+
+func dedupe(rules [][]string) [][]string {
+	seen := make(map[string]bool, len(rules))
+	list := make([][]string, 0, len(rules))
+	for _, rule := range rules {
+		key := ruleKey(rule)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		list = append(list, slices.Clone(rule))
+	}
+	return list
+}
+
+func ruleKey(rule []string) string {
+	return strings.Join(rule, "\x00")
+}
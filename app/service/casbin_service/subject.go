@@ -0,0 +1,23 @@
+package casbin_service
+
+// Casbin 主体前缀，与 AddPolicyView 中的 subType 对应
+const (
+	SubjectTypeUser    = "user"
+	SubjectTypeRole    = "role"
+	SubjectTypeService = "service"
+)
+
+// UserSubject 用户主体 user:<id>
+func UserSubject(id string) string {
+	return SubjectTypeUser + ":" + id
+}
+
+// ServiceSubject 服务账号主体 service:<id>
+func ServiceSubject(id string) string {
+	return SubjectTypeService + ":" + id
+}
+
+// RoleSubject 角色主体 role:<id>
+func RoleSubject(id string) string {
+	return SubjectTypeRole + ":" + id
+}
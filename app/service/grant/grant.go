@@ -0,0 +1,280 @@
+// package grant: 临时授权，申请经他人审批后生效，到期自动回收
+package grant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gpm/app/model"
+	"gpm/app/service/casbin_service"
+	"gpm/global"
+	"time"
+
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"gorm.io/gorm"
+)
+
+// 授权类型
+const (
+	KindRole   = "role"   // 临时分配角色
+	KindPolicy = "policy" // 临时直接授权
+)
+
+// 临时授权状态
+const (
+	StatusPending  = "pending"
+	StatusActive   = "active"
+	StatusRejected = "rejected"
+	StatusExpired  = "expired"
+	StatusRevoked  = "revoked"
+)
+
+// DefaultMaxDuration 临时授权默认最长时长
+const DefaultMaxDuration = 24 * time.Hour
+
+var (
+	ErrGrantNotFound = errors.New("临时授权不存在")
+	ErrGrantStatus   = errors.New("临时授权当前状态不允许该操作")
+	ErrSelfApprove   = errors.New("不能审批自己申请或授予自己（含自己所属角色）的临时授权")
+	ErrGrantExists   = errors.New("主体已拥有该权限，无需临时授权")
+	ErrDuration      = errors.New("临时授权时长超出上限")
+)
+
+// Validate 校验授权时长、角色归属与授权规则
+func Validate(ctx context.Context, g *model.TemporaryGrant) error {
+	if time.Duration(g.Duration)*time.Second > maxDuration() {
+		return ErrDuration
+	}
+	switch g.Kind {
+	case KindRole:
+		role := casbin_service.DecodeSubject(g.Role)
+		if role.Type != casbin_service.SubjectTypeRole {
+			return fmt.Errorf("角色主体不正确: %s", g.Role)
+		}
+		var count int64
+		err := global.DB.WithContext(ctx).Model(&model.Role{}).
+			Where("tenant_id = ? AND id = ?", g.TenantID, role.Id).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.New("角色不属于当前租户")
+		}
+		return nil
+	case KindPolicy:
+		_, r := ruleOf(g)
+		return casbin_service.ValidatePolicy(r)
+	default:
+		return fmt.Errorf("未知的授权类型: %s", g.Kind)
+	}
+}
+
+// Request 发起临时授权申请，等待他人审批
+func Request(ctx context.Context, g *model.TemporaryGrant) error {
+	if err := Validate(ctx, g); err != nil {
+		return err
+	}
+	g.Status = StatusPending
+	return global.DB.WithContext(ctx).Create(g).Error
+}
+
+// Grant 管理员直接授予他人临时权限，立即生效，授予人记为审批人
+func Grant(ctx context.Context, g *model.TemporaryGrant) error {
+	if err := checkSubject(g, g.RequesterID); err != nil {
+		return err
+	}
+	if err := Request(ctx, g); err != nil {
+		return err
+	}
+	return activate(ctx, g, g.RequesterID, "")
+}
+
+// Approve 审批通过并写入 Casbin，有效期从审批时起算
+func Approve(ctx context.Context, tenant, id, approverID, comment string) error {
+	g, err := get(ctx, tenant, id)
+	if err != nil {
+		return err
+	}
+	if err = checkApprover(g, approverID); err != nil {
+		return err
+	}
+	return activate(ctx, g, approverID, comment)
+}
+
+// activate 生效待审批的授权，规则已存在时拒绝，避免到期回收误删原有授权
+func activate(ctx context.Context, g *model.TemporaryGrant, approverID, comment string) error {
+	if g.Status != StatusPending {
+		return ErrGrantStatus
+	}
+	ptype, r := ruleOf(g)
+	if has(ptype, r) {
+		return ErrGrantExists
+	}
+	now := time.Now()
+	// 先抢占状态，避免并发审批重复写入
+	err := update(ctx, g, StatusPending, map[string]any{
+		"status":      StatusActive,
+		"approver_id": approverID,
+		"comment":     comment,
+		"approved_at": int(now.Unix()),
+		"expire_at":   int(now.Add(time.Duration(g.Duration) * time.Second).Unix()),
+	})
+	if err != nil {
+		return err
+	}
+	if err = add(ptype, r); err != nil {
+		_ = global.DB.WithContext(ctx).Model(g).Updates(map[string]any{
+			"status": StatusPending, "approver_id": "", "comment": "", "approved_at": 0, "expire_at": 0,
+		}).Error
+		return err
+	}
+	return nil
+}
+
+// Reject 驳回申请
+func Reject(ctx context.Context, tenant, id, approverID, comment string) error {
+	g, err := get(ctx, tenant, id)
+	if err != nil {
+		return err
+	}
+	if err = checkApprover(g, approverID); err != nil {
+		return err
+	}
+	return update(ctx, g, StatusPending, map[string]any{
+		"status":      StatusRejected,
+		"approver_id": approverID,
+		"comment":     comment,
+	})
+}
+
+// Revoke 提前回收已生效的授权，或撤回待审批的申请
+func Revoke(ctx context.Context, tenant, id string) error {
+	g, err := get(ctx, tenant, id)
+	if err != nil {
+		return err
+	}
+	switch g.Status {
+	case StatusPending:
+		return update(ctx, g, StatusPending, map[string]any{"status": StatusRevoked, "revoked_at": int(time.Now().Unix())})
+	case StatusActive:
+		return finish(ctx, g, StatusRevoked)
+	default:
+		return ErrGrantStatus
+	}
+}
+
+// finish 移除 Casbin 规则后结束授权，移除操作可重复执行，多个实例同时回收时只有一个状态更新成功
+func finish(ctx context.Context, g *model.TemporaryGrant, status string) error {
+	ptype, r := ruleOf(g)
+	if err := remove(ptype, r); err != nil {
+		return err
+	}
+	return update(ctx, g, StatusActive, map[string]any{"status": status, "revoked_at": int(time.Now().Unix())})
+}
+
+// checkApprover 审批人不能是申请人，也不能是被授权的主体
+func checkApprover(g *model.TemporaryGrant, approverID string) error {
+	if approverID == g.RequesterID {
+		return ErrSelfApprove
+	}
+	return checkSubject(g, approverID)
+}
+
+// checkSubject 被授权主体不能是用户本人，也不能是用户所属（含继承）的角色，否则等同于给自己授权
+func checkSubject(g *model.TemporaryGrant, userID string) error {
+	inherits, err := casbin_service.Inherits(casbin_service.UserSubject(userID), g.Subject, g.TenantID)
+	if err != nil {
+		return err
+	}
+	if inherits {
+		return ErrSelfApprove
+	}
+	return nil
+}
+
+func get(ctx context.Context, tenant, id string) (*model.TemporaryGrant, error) {
+	var g model.TemporaryGrant
+	err := global.DB.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenant, id).Take(&g).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrGrantNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// update 仅在授权仍处于 from 状态时更新
+func update(ctx context.Context, g *model.TemporaryGrant, from string, values map[string]any) error {
+	result := global.DB.WithContext(ctx).Model(&model.TemporaryGrant{}).
+		Where("id = ? AND status = ?", g.ID, from).Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrGrantStatus
+	}
+	return nil
+}
+
+func maxDuration() time.Duration {
+	if n := global.Config.Grant.MaxDuration; n > 0 {
+		return time.Duration(n) * time.Second
+	}
+	return DefaultMaxDuration
+}
+
+// Rules 租户内生效中的临时授权规则，配置包导出与导入时排除，避免当作永久授权导出或被 prune 删除
+func Rules(db *gorm.DB, tenant string) ([]gormadapter.CasbinRule, error) {
+	var list []model.TemporaryGrant
+	if err := db.Where("tenant_id = ? AND status = ?", tenant, StatusActive).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	rules := make([]gormadapter.CasbinRule, 0, len(list))
+	for i := range list {
+		ptype, r := ruleOf(&list[i])
+		rule := gormadapter.CasbinRule{Ptype: ptype, V0: r[0], V1: r[1], V2: r[2]}
+		if ptype == "p" {
+			rule.V3, rule.V4, rule.V5 = r[3], r[4], r[5]
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ruleOf 授权对应的 Casbin 规则，角色分配为 g 规则 [sub, role, dom]
+func ruleOf(g *model.TemporaryGrant) (ptype string, r []string) {
+	if g.Kind == KindRole {
+		return "g", []string{g.Subject, g.Role, g.TenantID}
+	}
+	return "p", casbin_service.NewPolicy(g.Subject, g.TenantID, g.Obj, g.Act, "", casbin_service.EffectAllow)
+}
+
+func has(ptype string, r []string) bool {
+	if ptype == "g" {
+		ok, _ := global.CasbinEnforcer.HasGroupingPolicy(r)
+		return ok
+	}
+	ok, _ := global.CasbinEnforcer.HasPolicy(r)
+	return ok
+}
+
+func add(ptype string, r []string) error {
+	var err error
+	if ptype == "g" {
+		_, err = global.CasbinEnforcer.AddGroupingPolicy(r)
+	} else {
+		_, err = global.CasbinEnforcer.AddPolicy(r)
+	}
+	return err
+}
+
+func remove(ptype string, r []string) error {
+	var err error
+	if ptype == "g" {
+		_, err = global.CasbinEnforcer.RemoveGroupingPolicy(r)
+	} else {
+		_, err = global.CasbinEnforcer.RemovePolicy(r)
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+package grant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gpm/app/model"
+	"gpm/app/service/audit"
+	"gpm/app/service/log"
+	"gpm/global"
+	"time"
+)
+
+// DefaultSweepInterval 到期授权默认回收间隔
+const DefaultSweepInterval = time.Minute
+
+// Sweep 回收已到期的临时授权并写入操作日志，返回本实例回收的数量
+func Sweep(ctx context.Context) (int, error) {
+	var list []model.TemporaryGrant
+	err := global.DB.WithContext(ctx).
+		Where("status = ? AND expire_at <= ?", StatusActive, time.Now().Unix()).
+		Find(&list).Error
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for i := range list {
+		g := &list[i]
+		if err = finish(ctx, g, StatusExpired); err != nil {
+			if !errors.Is(err, ErrGrantStatus) {
+				log.Ctx(ctx).Errorf("临时授权 %s 回收失败: %s", g.ID, err)
+			}
+			continue
+		}
+		count++
+		actionLog := model.ActionLog{
+			LogID:    log.ResolveLogId("", ""),
+			UserID:   g.RequesterID,
+			Action:   fmt.Sprintf("临时授权到期回收 %s", Describe(g)),
+			Tenant:   g.TenantID,
+			Duration: "0",
+		}
+		if err = audit.AppendActionLog(ctx, &actionLog); err != nil {
+			log.Ctx(ctx).Errorf("临时授权 %s 回收日志写入失败: %s", g.ID, err)
+		}
+	}
+	return count, nil
+}
+
+// RunSweeper 按固定间隔回收到期授权，直到 ctx 结束
+func RunSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := Sweep(ctx)
+			if err != nil {
+				log.Ctx(ctx).Errorf("临时授权回收失败: %s", err)
+				continue
+			}
+			if n > 0 {
+				log.Ctx(ctx).Infof("已回收%d个到期临时授权", n)
+			}
+		}
+	}
+}
+
+// Describe 操作日志中的授权描述
+func Describe(g *model.TemporaryGrant) string {
+	if g.Kind == KindRole {
+		return fmt.Sprintf("%s: %s -> %s", g.ID, g.Subject, g.Role)
+	}
+	return fmt.Sprintf("%s: %s -> %s %s", g.ID, g.Subject, g.Obj, g.Act)
+}
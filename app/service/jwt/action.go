@@ -0,0 +1,52 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"gpm/global"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrActionTokenInvalid = errors.New("链接无效或已过期")
+
+// ActionClaims 邮件链接中的一次性令牌，是否已使用由调用方按 jti 记录
+type ActionClaims struct {
+	Id      string `json:"id"`
+	Purpose string `json:"purpose"`
+	Type    string `json:"type"`
+	jwt.RegisteredClaims
+}
+
+// GenActionToken 生成指定用途的令牌
+func (j *JWT) GenActionToken(userId, purpose, jti string, expire time.Duration) (string, error) {
+	now := time.Now()
+	claims := ActionClaims{
+		Id:      userId,
+		Purpose: purpose,
+		Type:    "action",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(expire)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    global.Config.Jwt.Issuer,
+		},
+	}
+	return sign(claims, global.Config.Jwt.AccessSecret)
+}
+
+// ParseActionToken 解析令牌并校验用途
+func (j *JWT) ParseActionToken(tokenString, purpose string) (*ActionClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &ActionClaims{}, keyfunc(global.Config.Jwt.AccessSecret))
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrActionTokenInvalid
+		}
+		return nil, fmt.Errorf("解析令牌失败: %w", err)
+	}
+	if claims, ok := token.Claims.(*ActionClaims); ok && token.Valid && claims.Type == "action" && claims.Purpose == purpose {
+		return claims, nil
+	}
+	return nil, ErrActionTokenInvalid
+}
@@ -0,0 +1,38 @@
+package jwt
+
+import (
+	"gpm/app/service/casbin_service"
+	"gpm/global"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ImpersonateExpire 模拟登录令牌默认有效期
+const ImpersonateExpire = 15 * time.Minute
+
+// Actor 真实操作人，对应 RFC 8693 的 act 声明
+type Actor struct {
+	Sub string `json:"sub"`
+}
+
+// GenImpersonateToken 以目标用户身份签发访问令牌，act 记录真实操作人，不签发刷新令牌
+func (j *JWT) GenImpersonateToken(tenant, userId, actorId, sid string, expire time.Duration) (string, error) {
+	now := time.Now()
+	claims := AccessClaims{
+		UserClaims: UserClaims{
+			Id:   userId,
+			Role: global.CasbinEnforcer.GetRolesForUserInDomain(casbin_service.UserSubject(userId), tenant),
+		},
+		Type: "access",
+		Sid:  sid,
+		Act:  &Actor{Sub: actorId},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(expire)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    global.Config.Jwt.Issuer,
+		},
+	}
+	return sign(claims, global.Config.Jwt.AccessSecret)
+}
@@ -1,8 +1,11 @@
 package jwt
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"gpm/app/service/casbin_service"
+	"gpm/app/service/keyring"
 	"gpm/global"
 	"time"
 
@@ -15,6 +18,8 @@ import (
 type AccessClaims struct {
 	UserClaims
 	Type string `json:"type"`
+	Sid  string `json:"sid,omitempty"` // 登录会话标识
+	Act  *Actor `json:"act,omitempty"` // 模拟登录时的真实操作人
 	jwt.RegisteredClaims
 }
 
@@ -27,9 +32,33 @@ type UserClaims struct {
 type RefreshClaims struct {
 	Id   string `json:"id"`
 	Type string `json:"type"`
+	Sid  string `json:"sid,omitempty"` // 登录会话标识
 	jwt.RegisteredClaims
 }
 
+var (
+	ErrAccessTokenExpired  = errors.New("访问令牌已过期")
+	ErrAccessTokenInvalid  = errors.New("无效的访问令牌")
+	ErrRefreshTokenExpired = errors.New("刷新令牌已过期")
+	ErrRefreshTokenInvalid = errors.New("无效的刷新令牌")
+)
+
+// FailReason 将令牌解析错误归类为指标标签
+func FailReason(err error) string {
+	switch {
+	case errors.Is(err, ErrAccessTokenExpired), errors.Is(err, ErrRefreshTokenExpired):
+		return "expired"
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return "invalid_signature"
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return "malformed"
+	case errors.Is(err, jwt.ErrTokenNotValidYet):
+		return "not_valid_yet"
+	default:
+		return "invalid"
+	}
+}
+
 // BaseClaims 基础声明，包含用户ID
 type BaseClaims struct {
 	Id uint `json:"id"`
@@ -44,55 +73,71 @@ func NewJWT() *JWT {
 	return &JWT{}
 }
 
+// sign 按配置算法签名：非对称算法使用密钥环并写入 kid，HS256 使用对应的共享密钥
+func sign(claims jwt.Claims, secret string) (string, error) {
+	if keyring.Asymmetric(global.Config.Jwt.Alg) {
+		return keyring.Sign(context.Background(), claims)
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+}
+
+// keyfunc 选择验签密钥，签名方法必须与配置一致，防止算法混淆
+func keyfunc(secret string) jwt.Keyfunc {
+	if keyring.Asymmetric(global.Config.Jwt.Alg) {
+		return keyring.Keyfunc(context.Background())
+	}
+	return func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("意外的签名方法: %v", token.Header["alg"])
+		}
+		return []byte(secret), nil
+	}
+}
+
 // generateAccessToken 生成访问令牌
-func (j *JWT) generateAccessToken(id string, roles []string) (string, error) {
+func (j *JWT) generateAccessToken(id, sid string, roles []string) (string, error) {
 	claims := AccessClaims{
 		UserClaims: UserClaims{
 			Id:   id,
 			Role: roles,
 		},
 		Type: "access",
+		Sid:  sid,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(global.Config.Jwt.AccessExpire))),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(global.Config.Jwt.AccessExpire) * time.Second)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    global.Config.Jwt.Issuer,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(global.Config.Jwt.AccessSecret))
+	return sign(claims, global.Config.Jwt.AccessSecret)
 }
 
 // generateRefreshToken 生成刷新令牌
-func (j *JWT) generateRefreshToken(id string) (string, error) {
+func (j *JWT) generateRefreshToken(id, sid string) (string, error) {
 	claims := RefreshClaims{
 		Id:   id,
 		Type: "refresh",
+		Sid:  sid,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(global.Config.Jwt.RefreshExpire))),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(global.Config.Jwt.RefreshExpire) * time.Second)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    global.Config.Jwt.Issuer,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(global.Config.Jwt.RefreshSecret))
+	return sign(claims, global.Config.Jwt.RefreshSecret)
 }
 
 // ParseAccessToken 解析访问令牌
 func (j *JWT) ParseAccessToken(tokenString string) (*AccessClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &AccessClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("意外的签名方法: %v", token.Header["alg"])
-		}
-		return []byte(global.Config.Jwt.AccessSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &AccessClaims{}, keyfunc(global.Config.Jwt.AccessSecret))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
-			return nil, errors.New("访问令牌已过期")
+			return nil, ErrAccessTokenExpired
 		}
 		return nil, fmt.Errorf("解析访问令牌失败: %w", err)
 	}
@@ -100,21 +145,16 @@ func (j *JWT) ParseAccessToken(tokenString string) (*AccessClaims, error) {
 	if claims, ok := token.Claims.(*AccessClaims); ok && token.Valid && claims.Type == "access" {
 		return claims, nil
 	}
-	return nil, errors.New("无效的访问令牌")
+	return nil, ErrAccessTokenInvalid
 }
 
 // ParseRefreshToken 解析刷新令牌
 func (j *JWT) ParseRefreshToken(tokenString string) (*RefreshClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &RefreshClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("意外的签名方法: %v", token.Header["alg"])
-		}
-		return []byte(global.Config.Jwt.RefreshSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &RefreshClaims{}, keyfunc(global.Config.Jwt.RefreshSecret))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
-			return nil, errors.New("刷新令牌已过期")
+			return nil, ErrRefreshTokenExpired
 		}
 		return nil, fmt.Errorf("解析刷新令牌失败: %w", err)
 	}
@@ -123,7 +163,7 @@ func (j *JWT) ParseRefreshToken(tokenString string) (*RefreshClaims, error) {
 		return claims, nil
 	}
 
-	return nil, errors.New("无效的刷新令牌")
+	return nil, ErrRefreshTokenInvalid
 }
 
 type TokenPair struct {
@@ -138,13 +178,14 @@ func (j *JWT) RefreshTokens(refreshTokenString string, tenant string) (*TokenPai
 	if err != nil {
 		return nil, fmt.Errorf("刷新令牌无效: %w", err)
 	}
-	roles := global.CasbinEnforcer.GetRolesForUserInDomain(claims.ID, tenant)
-	accessToken, err := j.generateAccessToken(claims.Id, roles)
+	roles := global.CasbinEnforcer.GetRolesForUserInDomain(casbin_service.UserSubject(claims.Id), tenant)
+	accessToken, err := j.generateAccessToken(claims.Id, claims.Sid, roles)
 	if err != nil {
 		return nil, err
 	}
-	if time.Now().Sub(claims.ExpiresAt.Time) < 24*3*time.Hour {
-		refreshTokenString, err = j.generateRefreshToken(claims.Id)
+	// 刷新令牌剩余有效期不足 3 天时一并续期
+	if time.Until(claims.ExpiresAt.Time) < 24*3*time.Hour {
+		refreshTokenString, err = j.generateRefreshToken(claims.Id, claims.Sid)
 		if err != nil {
 			return nil, err
 		}
@@ -155,13 +196,14 @@ func (j *JWT) RefreshTokens(refreshTokenString string, tenant string) (*TokenPai
 	return &TokenPair{AccessToken: accessToken, RefreshToken: refreshTokenString}, nil
 }
 
-func (j *JWT) GenPairToken(tenant string, userId string) (*TokenPair, error) {
-	roles := global.CasbinEnforcer.GetRolesForUserInDomain(userId, tenant)
-	refreshToken, err := j.generateRefreshToken(userId)
+// GenPairToken 签发令牌对，sid 为登录会话标识，见 service/session
+func (j *JWT) GenPairToken(tenant string, userId string, sid string) (*TokenPair, error) {
+	roles := global.CasbinEnforcer.GetRolesForUserInDomain(casbin_service.UserSubject(userId), tenant)
+	refreshToken, err := j.generateRefreshToken(userId, sid)
 	if err != nil {
 		return nil, err
 	}
-	accessToken, err := j.generateAccessToken(userId, roles)
+	accessToken, err := j.generateAccessToken(userId, sid, roles)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,56 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"gpm/global"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// MfaExpire 密码校验通过后完成 MFA 的时限
+const MfaExpire = 5 * time.Minute
+
+var ErrMfaTokenInvalid = errors.New("MFA 令牌无效或已过期")
+
+// MfaClaims 登录第一步签发的中间令牌，只能用于 MFA 校验或绑定
+type MfaClaims struct {
+	Id     string `json:"id"`
+	Tenant string `json:"tenant"`
+	Type   string `json:"type"`
+	jwt.RegisteredClaims
+}
+
+// GenMfaToken 生成 MFA 中间令牌，是否已使用由调用方按 jti 记录
+func (j *JWT) GenMfaToken(tenant, userId, jti string) (string, error) {
+	now := time.Now()
+	claims := MfaClaims{
+		Id:     userId,
+		Tenant: tenant,
+		Type:   "mfa",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(MfaExpire)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    global.Config.Jwt.Issuer,
+		},
+	}
+	return sign(claims, global.Config.Jwt.AccessSecret)
+}
+
+// ParseMfaToken 解析 MFA 中间令牌
+func (j *JWT) ParseMfaToken(tokenString string) (*MfaClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &MfaClaims{}, keyfunc(global.Config.Jwt.AccessSecret))
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrMfaTokenInvalid
+		}
+		return nil, fmt.Errorf("解析 MFA 令牌失败: %w", err)
+	}
+	if claims, ok := token.Claims.(*MfaClaims); ok && token.Valid && claims.Type == "mfa" {
+		return claims, nil
+	}
+	return nil, ErrMfaTokenInvalid
+}
@@ -0,0 +1,100 @@
+package keyring
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"gpm/app/model"
+	"gpm/global"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// 支持的非对称签名算法
+var algorithms = []string{
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodES256.Alg(),
+	jwt.SigningMethodEdDSA.Alg(),
+}
+
+// Asymmetric 判断是否为密钥环支持的非对称算法
+func Asymmetric(alg string) bool {
+	for _, a := range algorithms {
+		if a == alg {
+			return true
+		}
+	}
+	return false
+}
+
+// Alg 密钥环使用的算法：jwt.alg 为非对称算法时沿用，否则默认 RS256（id_token 必须可被公钥验签）
+func Alg() string {
+	if Asymmetric(global.Config.Jwt.Alg) {
+		return global.Config.Jwt.Alg
+	}
+	return jwt.SigningMethodRS256.Alg()
+}
+
+func newSigner(alg string) (crypto.Signer, error) {
+	switch alg {
+	case jwt.SigningMethodRS256.Alg():
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case jwt.SigningMethodES256.Alg():
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case jwt.SigningMethodEdDSA.Alg():
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		return priv, err
+	default:
+		return nil, fmt.Errorf("不支持的签名算法: %s", alg)
+	}
+}
+
+func generate(db *gorm.DB, alg string) (*Key, error) {
+	signer, err := newSigner(alg)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, 8)
+	if _, err = rand.Read(b); err != nil {
+		return nil, err
+	}
+	row := model.SigningKey{
+		Kid:        hex.EncodeToString(b),
+		Alg:        alg,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		Status:     true,
+	}
+	if err = db.Create(&row).Error; err != nil {
+		return nil, err
+	}
+	return &Key{Kid: row.Kid, Alg: row.Alg, Signer: signer, CreateAt: row.CreateAt}, nil
+}
+
+func parseKey(row model.SigningKey) (*Key, error) {
+	block, _ := pem.Decode([]byte(row.PrivateKey))
+	if block == nil {
+		return nil, errors.New("私钥不是合法的 PEM")
+	}
+	priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("不支持的私钥类型")
+	}
+	return &Key{Kid: row.Kid, Alg: row.Alg, Signer: signer, CreateAt: row.CreateAt, ExpireAt: row.ExpireAt}, nil
+}
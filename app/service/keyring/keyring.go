@@ -0,0 +1,190 @@
+// package keyring: 非对称签名密钥环，私钥保存在数据库，公钥通过 JWKS 公布
+package keyring
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"gpm/app/model"
+	"gpm/global"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrKeyNotFound = errors.New("签名密钥不存在")
+
+// Key 已加载的签名密钥
+type Key struct {
+	Kid      string
+	Alg      string
+	Signer   crypto.Signer
+	CreateAt int
+	ExpireAt int
+}
+
+// Method 返回对应的 JWT 签名方法
+func (k *Key) Method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Alg)
+}
+
+// Public 返回公钥
+func (k *Key) Public() crypto.PublicKey {
+	return k.Signer.Public()
+}
+
+// reloadInterval 遇到未知 kid 时重新加载的最小间隔，用于获取其他实例轮换出的新密钥
+const reloadInterval = 10 * time.Second
+
+var (
+	loadMu   sync.Mutex // 串行化加载，避免并发首次使用时重复生成密钥
+	mu       sync.RWMutex
+	keys     map[string]*Key
+	current  *Key
+	loadedAt time.Time
+)
+
+// Load 从数据库加载全部有效密钥，没有可用密钥时按配置算法生成一把
+func Load(ctx context.Context) error {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+	return load(ctx)
+}
+
+func load(ctx context.Context) error {
+	var rows []model.SigningKey
+	now := int(time.Now().Unix())
+	err := global.DB.WithContext(ctx).Where("status = ? AND (expire_at = 0 OR expire_at > ?)", true, now).
+		Order("create_at desc").Find(&rows).Error
+	if err != nil {
+		return err
+	}
+	loaded := map[string]*Key{}
+	var cur *Key
+	for _, row := range rows {
+		key, err := parseKey(row)
+		if err != nil {
+			return fmt.Errorf("签名密钥 %s 解析失败: %w", row.Kid, err)
+		}
+		loaded[key.Kid] = key
+		// 未设置失效时间的密钥用于签名，已轮换的密钥仅用于验签
+		if row.ExpireAt == 0 && (cur == nil || key.CreateAt > cur.CreateAt) {
+			cur = key
+		}
+	}
+	if cur == nil {
+		if cur, err = generate(global.DB.WithContext(ctx), Alg()); err != nil {
+			return err
+		}
+		loaded[cur.Kid] = cur
+	}
+	mu.Lock()
+	keys, current, loadedAt = loaded, cur, time.Now()
+	mu.Unlock()
+	return nil
+}
+
+func ensureLoaded(ctx context.Context) error {
+	mu.RLock()
+	ok := current != nil
+	mu.RUnlock()
+	if ok {
+		return nil
+	}
+	loadMu.Lock()
+	defer loadMu.Unlock()
+	mu.RLock()
+	ok = current != nil
+	mu.RUnlock()
+	if ok {
+		return nil
+	}
+	return load(ctx)
+}
+
+// Current 返回当前用于签名的密钥
+func Current(ctx context.Context) (*Key, error) {
+	if err := ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	return current, nil
+}
+
+// Lookup 按 kid 查找验签密钥，未找到时按间隔重新加载一次
+func Lookup(ctx context.Context, kid string) (*Key, error) {
+	if err := ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+	key, ok := lookup(kid)
+	if !ok {
+		mu.RLock()
+		stale := time.Since(loadedAt) > reloadInterval
+		mu.RUnlock()
+		if stale {
+			if err := Load(ctx); err != nil {
+				return nil, err
+			}
+			key, ok = lookup(kid)
+		}
+	}
+	if !ok || (key.ExpireAt > 0 && int64(key.ExpireAt) <= time.Now().Unix()) {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+func lookup(kid string) (*Key, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	key, ok := keys[kid]
+	return key, ok
+}
+
+// JWKS 返回全部有效公钥
+func JWKS(ctx context.Context) (jose.JSONWebKeySet, error) {
+	if err := ensureLoaded(ctx); err != nil {
+		return jose.JSONWebKeySet{}, err
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	now := time.Now().Unix()
+	set := jose.JSONWebKeySet{Keys: make([]jose.JSONWebKey, 0, len(keys))}
+	for _, key := range keys {
+		if key.ExpireAt > 0 && int64(key.ExpireAt) <= now {
+			continue
+		}
+		set.Keys = append(set.Keys, jose.JSONWebKey{Key: key.Public(), KeyID: key.Kid, Algorithm: key.Alg, Use: "sig"})
+	}
+	return set, nil
+}
+
+// Keyfunc 供 jwt.Parse 使用，按头部 kid 选择公钥并校验算法
+func Keyfunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := Lookup(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != key.Alg {
+			return nil, fmt.Errorf("意外的签名方法: %v", token.Header["alg"])
+		}
+		return key.Public(), nil
+	}
+}
+
+// Sign 使用当前密钥签发 JWT
+func Sign(ctx context.Context, claims jwt.Claims) (string, error) {
+	key, err := Current(ctx)
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(key.Method(), claims)
+	token.Header["kid"] = key.Kid
+	return token.SignedString(key.Signer)
+}
@@ -0,0 +1,69 @@
+package keyring
+
+import (
+	"context"
+	"gpm/app/model"
+	"gpm/app/service/log"
+	"gpm/global"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Rotate 生成新签名密钥，旧密钥在 grace 后失效，期间仍可验签
+// 多实例同时轮换时通过 advisory lock 串行，maxAge 大于 0 时仅在当前密钥超龄或算法变更时轮换
+func Rotate(ctx context.Context, grace, maxAge time.Duration) (rotated bool, err error) {
+	alg := Alg()
+	err = global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('signing_key'))").Error; err != nil {
+			return err
+		}
+		var cur model.SigningKey
+		err := tx.Where("status = ? AND expire_at = 0", true).Order("create_at desc").Limit(1).Find(&cur).Error
+		if err != nil {
+			return err
+		}
+		if maxAge > 0 && cur.ID != "" && cur.Alg == alg && time.Since(time.Unix(int64(cur.CreateAt), 0)) < maxAge {
+			return nil
+		}
+		err = tx.Model(&model.SigningKey{}).Where("status = ? AND expire_at = 0", true).
+			Update("expire_at", int(time.Now().Add(grace).Unix())).Error
+		if err != nil {
+			return err
+		}
+		if _, err = generate(tx, alg); err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return rotated, Load(ctx)
+}
+
+// RunRotateTicker 定期检查是否需要轮换，并重新加载其他实例轮换出的密钥
+func RunRotateTicker(ctx context.Context, interval, grace time.Duration) {
+	check := min(interval/10, time.Hour)
+	if check < time.Minute {
+		check = time.Minute
+	}
+	ticker := time.NewTicker(check)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rotated, err := Rotate(ctx, grace, interval)
+			if err != nil {
+				log.Ctx(ctx).Errorf("签名密钥轮换失败: %s", err)
+				continue
+			}
+			if rotated {
+				log.Ctx(ctx).Info("签名密钥已轮换")
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package log
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ctxKey 请求上下文键，使用独立类型避免与其他包冲突
+type ctxKey int
+
+const (
+	logIdKey ctxKey = iota
+	tenantKey
+	userIdKey
+)
+
+// WithLogId 在上下文中写入日志ID
+func WithLogId(ctx context.Context, logId string) context.Context {
+	return context.WithValue(ctx, logIdKey, logId)
+}
+
+// WithTenant 在上下文中写入租户
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey, tenant)
+}
+
+// WithUserId 在上下文中写入用户ID
+func WithUserId(ctx context.Context, userId string) context.Context {
+	return context.WithValue(ctx, userIdKey, userId)
+}
+
+func value(ctx context.Context, key ctxKey) string {
+	if ctx == nil {
+		return ""
+	}
+	v, _ := ctx.Value(key).(string)
+	return v
+}
+
+// LogIdFrom 读取上下文中的日志ID
+func LogIdFrom(ctx context.Context) string {
+	return value(ctx, logIdKey)
+}
+
+// TenantFrom 读取上下文中的租户
+func TenantFrom(ctx context.Context) string {
+	return value(ctx, tenantKey)
+}
+
+// UserIdFrom 读取上下文中的用户ID
+func UserIdFrom(ctx context.Context) string {
+	return value(ctx, userIdKey)
+}
+
+// Fields 返回上下文中的请求级日志字段，存在链路追踪时附带 traceId
+func Fields(ctx context.Context) logrus.Fields {
+	fields := logrus.Fields{
+		"logId":  LogIdFrom(ctx),
+		"tenant": TenantFrom(ctx),
+		"userId": UserIdFrom(ctx),
+	}
+	if ctx != nil {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			fields["traceId"] = sc.TraceID().String()
+		}
+	}
+	return fields
+}
+
+// Ctx 返回携带请求上下文的日志条目，公共字段由日志 hook 统一补充
+func Ctx(ctx context.Context) *logrus.Entry {
+	return logrus.WithContext(ctx)
+}
+
+// Detach 复制请求级字段和链路信息到新的后台上下文，不继承取消信号，供异步任务使用
+func Detach(ctx context.Context) context.Context {
+	detached := trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+	if logId := LogIdFrom(ctx); logId != "" {
+		detached = WithLogId(detached, logId)
+	}
+	if tenant := TenantFrom(ctx); tenant != "" {
+		detached = WithTenant(detached, tenant)
+	}
+	if userId := UserIdFrom(ctx); userId != "" {
+		detached = WithUserId(detached, userId)
+	}
+	return detached
+}
+
+// Go 在新协程中执行 fn，保留请求级字段并记录 panic
+func Go(ctx context.Context, fn func(ctx context.Context)) {
+	detached := Detach(ctx)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				Ctx(detached).WithField("stack", string(debug.Stack())).Errorf("协程异常: %v", r)
+			}
+		}()
+		fn(detached)
+	}()
+}
@@ -4,9 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"gpm/app/service/metrics"
+	"gpm/app/service/telemetry"
+	"strings"
+	"time"
+
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm/logger"
-	"time"
 )
 
 // GormLogger 实现 logger.Interface 接口
@@ -33,17 +40,17 @@ func (l *GormLogger) LogMode(level logger.LogLevel) logger.Interface {
 
 // Info 打印信息日志
 func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
-	logrus.WithContext(ctx).Infof(msg, data...)
+	Ctx(ctx).Infof(msg, data...)
 }
 
 // Warn 打印警告日志
 func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
-	logrus.WithContext(ctx).Warnf(msg, data...)
+	Ctx(ctx).Warnf(msg, data...)
 }
 
 // Error 打印错误日志
 func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
-	logrus.WithContext(ctx).Errorf(msg, data...)
+	Ctx(ctx).Errorf(msg, data...)
 }
 
 // Trace 打印SQL语句和耗时
@@ -54,7 +61,7 @@ func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql
 	elapsed := time.Since(begin)
 	elapsedMs := float64(elapsed.Nanoseconds()) / 1e6
 
-	// 获取包含 logId 的 logrus.Fields
+	// logId、租户、用户等请求级字段由日志 hook 根据 ctx 补充
 	fields := logrus.Fields{}
 	fields["rows"] = rows
 	fields["duration"] = fmt.Sprintf("%.5f", elapsedMs) // 保留3位小数
@@ -63,15 +70,47 @@ func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql
 	if len(sql) > 1*1024 {
 		fields["sql"] = ""
 	}
+	l.traceSpan(ctx, begin, elapsed, sql, rows, err)
 	switch {
 	case err != nil && !errors.Is(err, logger.ErrRecordNotFound):
 		// 发生错误
-		logrus.WithContext(ctx).WithFields(fields).Error("Database Error")
+		Ctx(ctx).WithFields(fields).Error("Database Error")
 	case elapsed > l.SlowThreshold:
 		// 慢查询
-		logrus.WithContext(ctx).WithFields(fields).Warn("Database Slow Query")
+		metrics.SlowQueries.Inc()
+		Ctx(ctx).WithFields(fields).Warn("Database Slow Query")
 	default:
 		// 正常查询
-		logrus.WithContext(ctx).WithFields(fields).Debug("Database Query")
+		Ctx(ctx).WithFields(fields).Debug("Database Query")
+	}
+}
+
+// traceSpan 补录一条 SQL span，仅在请求已有链路时记录，避免后台查询产生大量孤立 trace
+func (l *GormLogger) traceSpan(ctx context.Context, begin time.Time, elapsed time.Duration, sql string, rows int64, err error) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+	operation, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+	operation = strings.ToUpper(operation)
+	if len(sql) > 4*1024 {
+		sql = sql[:4*1024]
+	}
+	_, span := telemetry.Tracer().Start(ctx, "db "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(begin),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "postgresql"),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.query.text", sql),
+			attribute.Int64("db.response.returned_rows", rows),
+		),
+	)
+	if err != nil && !errors.Is(err, logger.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	if elapsed > l.SlowThreshold {
+		span.SetAttributes(attribute.Bool("gpm.db.slow", true))
 	}
+	span.End(trace.WithTimestamp(begin.Add(elapsed)))
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// LogByGin 返回携带当前请求 logId、租户和用户的日志条目
 func LogByGin(c *gin.Context, fields logrus.Fields) *logrus.Entry {
-	return logrus.WithFields(fields)
+	return Ctx(c.Request.Context()).WithFields(fields)
 }
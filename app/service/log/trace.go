@@ -0,0 +1,69 @@
+package log
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	HeaderRequestId   = "X-Request-Id"
+	HeaderTraceparent = "traceparent"
+)
+
+// maxRequestIdLen 外部传入请求ID的最大长度，与 ActionLog.LogID 字段长度一致
+const maxRequestIdLen = 128
+
+// ParseTraceparent 解析 W3C traceparent（version-traceid-parentid-flags），返回 trace-id
+func ParseTraceparent(traceparent string) (string, bool) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return "", false
+	}
+	if parts[0] == "ff" {
+		return "", false
+	}
+	for _, p := range parts {
+		if !isLowerHex(p) {
+			return "", false
+		}
+	}
+	if strings.Trim(parts[1], "0") == "" || strings.Trim(parts[2], "0") == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f')) {
+			return false
+		}
+	}
+	return true
+}
+
+// validRequestId 只接受可安全写入日志与响应头的请求ID
+func validRequestId(id string) bool {
+	if id == "" || len(id) > maxRequestIdLen {
+		return false
+	}
+	for _, c := range id {
+		if !((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == ':') {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveLogId 按 traceparent、X-Request-Id 的顺序沿用上游标识，都不可用时生成新的日志ID
+func ResolveLogId(traceparent, requestId string) string {
+	if traceId, ok := ParseTraceparent(traceparent); ok {
+		return traceId
+	}
+	if validRequestId(requestId) {
+		return requestId
+	}
+	return uuid.New().String()
+}
@@ -0,0 +1,39 @@
+// package mail: 邮件发送，按配置选择 SMTP、文件或日志实现
+package mail
+
+import (
+	"context"
+	"fmt"
+	"gpm/app/service/log"
+)
+
+// Message 待发送的邮件
+type Message struct {
+	To      string
+	Subject string
+	Body    string // 纯文本正文
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Sender 当前使用的发送实现，由 core.InitMail 按配置替换
+var Sender Mailer = LogMailer{}
+
+// Send 使用当前实现发送邮件
+func Send(ctx context.Context, msg Message) error {
+	if err := Sender.Send(ctx, msg); err != nil {
+		return fmt.Errorf("邮件发送失败: %w", err)
+	}
+	return nil
+}
+
+// LogMailer 只把邮件写入日志，用于开发环境
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Ctx(ctx).WithField("to", msg.To).WithField("subject", msg.Subject).Info(msg.Body)
+	return nil
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "gpm"
+
+// Registry gpm 自有的指标注册表，不使用全局默认注册表
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests 请求数
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "按路由、租户、方法、状态码统计的请求数",
+	}, []string{"route", "tenant", "method", "status"})
+	// HTTPDuration 请求耗时
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "按路由、租户统计的请求耗时",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "tenant", "method"})
+	// SignatureFailures 请求签名校验失败数
+	SignatureFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "signature_failures_total",
+		Help:      "ArgsCheckMiddleware 签名校验失败数",
+	}, []string{"reason"})
+	// JwtFailures JWT 校验失败数
+	JwtFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jwt_failures_total",
+		Help:      "JWT 校验失败数",
+	}, []string{"reason"})
+	// CasbinDecisions 权限判定结果数
+	CasbinDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "casbin_decisions_total",
+		Help:      "Casbin 权限判定结果（allow/deny/error）",
+	}, []string{"decision"})
+	// SlowQueries 慢查询数
+	SlowQueries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_slow_queries_total",
+		Help:      "GormLogger 检测到的慢查询数",
+	})
+	// ActionLogWriteFailures 操作日志写入失败数
+	ActionLogWriteFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "action_log_write_failures_total",
+		Help:      "操作日志写入数据库失败数",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		SignatureFailures,
+		JwtFailures,
+		CasbinDecisions,
+		SlowQueries,
+		ActionLogWriteFailures,
+	)
+}
+
+// Handler 返回 /metrics 处理器
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,281 @@
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"gpm/app/model"
+	"gpm/app/service/casbin_service"
+	"gpm/global"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrNotEnrolled     = errors.New("未绑定 MFA")
+	ErrAlreadyEnabled  = errors.New("MFA 已启用")
+	ErrCodeInvalid     = errors.New("验证码错误")
+	ErrRecoveryInvalid = errors.New("恢复码无效或已使用")
+	ErrLocked          = errors.New("验证失败次数过多，请稍后再试")
+)
+
+// RecoveryCodeCount 每次生成的恢复码数量
+const RecoveryCodeCount = 10
+
+const (
+	MaxFailures  = 5                // 连续失败达到该次数后锁定
+	LockDuration = 15 * time.Minute // 锁定时长
+)
+
+// Issuer otpauth 地址中显示的签发方
+func Issuer() string {
+	if global.Config.Jwt.Issuer != "" {
+		return global.Config.Jwt.Issuer
+	}
+	return global.Config.Log.App
+}
+
+// Enroll 生成新的 TOTP 密钥，验证通过前不生效；已启用时需先停用
+func Enroll(ctx context.Context, user *model.User) (secret, uri string, err error) {
+	var record model.UserMfa
+	db := global.DB.WithContext(ctx)
+	err = db.Where("user_id = ?", user.ID).Take(&record).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", "", err
+	}
+	if record.Enabled {
+		return "", "", ErrAlreadyEnabled
+	}
+	if secret, err = NewSecret(); err != nil {
+		return "", "", err
+	}
+	if record.ID == "" {
+		err = db.Create(&model.UserMfa{UserID: user.ID, Secret: secret}).Error
+	} else {
+		err = db.Model(&record).Updates(map[string]any{"secret": secret, "last_step": 0}).Error
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return secret, URI(Issuer(), user.Email, secret), nil
+}
+
+// Activate 校验首个验证码后启用 MFA，返回只可见一次的恢复码
+func Activate(ctx context.Context, userID, input string) ([]string, error) {
+	var codes []string
+	err := global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		record, err := lockRecord(tx, userID)
+		if err != nil {
+			return err
+		}
+		if record.Enabled {
+			return ErrAlreadyEnabled
+		}
+		step, ok := validate(record.Secret, input, time.Now(), record.LastStep)
+		if !ok {
+			return ErrCodeInvalid
+		}
+		err = tx.Model(record).Updates(map[string]any{"enabled": true, "last_step": step}).Error
+		if err != nil {
+			return err
+		}
+		codes, err = replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	return codes, err
+}
+
+// Enabled 用户是否已启用 MFA
+func Enabled(ctx context.Context, userID string) (bool, error) {
+	var count int64
+	err := global.DB.WithContext(ctx).Model(&model.UserMfa{}).
+		Where("user_id = ? AND enabled = ?", userID, true).Count(&count).Error
+	return count > 0, err
+}
+
+// Verify 校验 TOTP 验证码或恢复码，恢复码使用后立即失效
+// 连续失败 MaxFailures 次后锁定 LockDuration，锁定期间不再校验
+func Verify(ctx context.Context, userID, input string) error {
+	if err := checkLocked(ctx, userID); err != nil {
+		return err
+	}
+	input = strings.TrimSpace(input)
+	var err error
+	if strings.Contains(input, "-") {
+		err = useRecoveryCode(ctx, userID, input)
+	} else {
+		err = verifyCode(ctx, userID, input)
+	}
+	switch {
+	case errors.Is(err, ErrCodeInvalid), errors.Is(err, ErrRecoveryInvalid):
+		return recordFailure(ctx, userID, err)
+	case err == nil:
+		return global.DB.WithContext(ctx).Model(&model.UserMfa{}).
+			Where("user_id = ? AND failed_count > 0", userID).Update("failed_count", 0).Error
+	}
+	return err
+}
+
+func verifyCode(ctx context.Context, userID, input string) error {
+	return global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		record, err := lockRecord(tx, userID)
+		if err != nil {
+			return err
+		}
+		if !record.Enabled {
+			return ErrNotEnrolled
+		}
+		step, ok := validate(record.Secret, input, time.Now(), record.LastStep)
+		if !ok {
+			return ErrCodeInvalid
+		}
+		return tx.Model(record).Update("last_step", step).Error
+	})
+}
+
+// Disable 停用 MFA 并删除恢复码，需提供有效验证码或恢复码
+func Disable(ctx context.Context, userID, input string) error {
+	if err := Verify(ctx, userID, input); err != nil {
+		return err
+	}
+	return global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.MfaRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.UserMfa{}).Error
+	})
+}
+
+// RegenerateRecoveryCodes 校验验证码后重新生成恢复码，旧恢复码全部作废
+func RegenerateRecoveryCodes(ctx context.Context, userID, input string) ([]string, error) {
+	if err := Verify(ctx, userID, input); err != nil {
+		return nil, err
+	}
+	var codes []string
+	err := global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		codes, err = replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	return codes, err
+}
+
+// Required 按租户策略判断用户登录是否必须通过 MFA
+func Required(ctx context.Context, tenant, userID string) (bool, error) {
+	if tenant == "" {
+		return false, nil
+	}
+	var policy model.MfaPolicy
+	err := global.DB.WithContext(ctx).Where("tenant_id = ?", tenant).Take(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if policy.RequireAll {
+		return true, nil
+	}
+	roles := global.CasbinEnforcer.GetRolesForUserInDomain(casbin_service.UserSubject(userID), tenant)
+	for _, role := range policy.Roles {
+		if slices.Contains(roles, casbin_service.RoleSubject(role)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func checkLocked(ctx context.Context, userID string) error {
+	var count int64
+	err := global.DB.WithContext(ctx).Model(&model.UserMfa{}).
+		Where("user_id = ? AND locked_until > ?", userID, time.Now().Unix()).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrLocked
+	}
+	return nil
+}
+
+// recordFailure 累计失败次数，达到上限时锁定并清零计数
+func recordFailure(ctx context.Context, userID string, cause error) error {
+	var locked bool
+	err := global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		record, err := lockRecord(tx, userID)
+		if err != nil {
+			return err
+		}
+		values := map[string]any{"failed_count": record.FailedCount + 1}
+		if record.FailedCount+1 >= MaxFailures {
+			locked = true
+			values = map[string]any{"failed_count": 0, "locked_until": int(time.Now().Add(LockDuration).Unix())}
+		}
+		return tx.Model(record).Updates(values).Error
+	})
+	if err != nil && !errors.Is(err, ErrNotEnrolled) {
+		return err
+	}
+	if locked {
+		return ErrLocked
+	}
+	return cause
+}
+
+func lockRecord(tx *gorm.DB, userID string) (*model.UserMfa, error) {
+	var record model.UserMfa
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).Take(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID string) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.MfaRecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, RecoveryCodeCount)
+	rows := make([]model.MfaRecoveryCode, 0, RecoveryCodeCount)
+	for range RecoveryCodeCount {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		raw := hex.EncodeToString(b)
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		rows = append(rows, model.MfaRecoveryCode{UserID: userID, Hash: hashCode(code)})
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func useRecoveryCode(ctx context.Context, userID, code string) error {
+	result := global.DB.WithContext(ctx).Model(&model.MfaRecoveryCode{}).
+		Where("user_id = ? AND hash = ? AND used_at = 0", userID, hashCode(code)).
+		Update("used_at", int(time.Now().Unix()))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecoveryInvalid
+	}
+	return nil
+}
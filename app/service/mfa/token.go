@@ -0,0 +1,94 @@
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"gpm/app/model"
+	"gpm/app/service/jwt"
+	"gpm/global"
+	"time"
+)
+
+// PurposeLogin MFA 中间令牌在 user_token 中记录的用途
+const PurposeLogin = "mfa_login"
+
+// Challenge 需要 MFA 时的登录响应，Enrolled 为 false 时需先通过 mfa/enroll 绑定
+type Challenge struct {
+	MfaRequired bool   `json:"mfaRequired"`
+	MfaToken    string `json:"mfaToken"`
+	Enrolled    bool   `json:"enrolled"`
+}
+
+// NewChallenge 已启用 MFA 或租户策略要求 MFA 时签发中间令牌，不需要时返回 nil
+// 密码登录与外部身份登录共用，保证两种方式都要完成第二步
+func NewChallenge(ctx context.Context, tenant, userID string) (*Challenge, error) {
+	enabled, err := Enabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	required, err := Required(ctx, tenant, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled && !required {
+		return nil, nil
+	}
+	token, err := issueToken(ctx, tenant, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &Challenge{MfaRequired: true, MfaToken: token, Enrolled: enabled}, nil
+}
+
+// issueToken 签发 MFA 中间令牌并记录 jti
+func issueToken(ctx context.Context, tenant, userID string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	jti := hex.EncodeToString(b)
+	err := global.DB.WithContext(ctx).Create(&model.UserToken{
+		Jti:      jti,
+		UserID:   userID,
+		Purpose:  PurposeLogin,
+		ExpireAt: int(time.Now().Add(jwt.MfaExpire).Unix()),
+	}).Error
+	if err != nil {
+		return "", err
+	}
+	return jwt.NewJWT().GenMfaToken(tenant, userID, jti)
+}
+
+// ParseToken 校验签名、有效期与使用记录，已签发过令牌对的中间令牌视为无效
+func ParseToken(ctx context.Context, raw string) (*jwt.MfaClaims, error) {
+	claims, err := jwt.NewJWT().ParseMfaToken(raw)
+	if err != nil {
+		return nil, err
+	}
+	var count int64
+	err = global.DB.WithContext(ctx).Model(&model.UserToken{}).
+		Where("jti = ? AND user_id = ? AND purpose = ? AND used_at = 0", claims.ID, claims.Id, PurposeLogin).
+		Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, jwt.ErrMfaTokenInvalid
+	}
+	return claims, nil
+}
+
+// ConsumeToken 签发令牌对前标记中间令牌已使用，并发请求只有一个成功
+func ConsumeToken(ctx context.Context, claims *jwt.MfaClaims) error {
+	result := global.DB.WithContext(ctx).Model(&model.UserToken{}).
+		Where("jti = ? AND user_id = ? AND purpose = ? AND used_at = 0", claims.ID, claims.Id, PurposeLogin).
+		Update("used_at", int(time.Now().Unix()))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return jwt.ErrMfaTokenInvalid
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package oauth
+
+import (
+	"sync"
+	"time"
+)
+
+// AuthCode 授权码关联的授权信息
+type AuthCode struct {
+	ClientID            string
+	UserID              string
+	Sid                 string // 用户登录 gpm 的会话，换取令牌时校验是否已吊销
+	Tenant              string
+	RedirectURI         string
+	Scope               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	AuthTime            time.Time
+	ExpireAt            time.Time
+}
+
+// CodeStore 授权码存储，多实例部署时可替换为共享存储实现
+type CodeStore interface {
+	Save(code string, c AuthCode) error
+	// Take 取出并删除授权码，保证只能使用一次
+	Take(code string) (c AuthCode, ok bool, err error)
+}
+
+// Codes 当前使用的授权码存储
+var Codes CodeStore = NewMemoryCodeStore()
+
+// MemoryCodeStore 单实例内存存储
+type MemoryCodeStore struct {
+	mu    sync.Mutex
+	items map[string]AuthCode
+}
+
+func NewMemoryCodeStore() *MemoryCodeStore {
+	return &MemoryCodeStore{items: map[string]AuthCode{}}
+}
+
+func (m *MemoryCodeStore) Save(code string, c AuthCode) error {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, item := range m.items {
+		if now.After(item.ExpireAt) {
+			delete(m.items, k)
+		}
+	}
+	m.items[code] = c
+	return nil
+}
+
+func (m *MemoryCodeStore) Take(code string) (AuthCode, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.items[code]
+	if !ok {
+		return AuthCode{}, false, nil
+	}
+	delete(m.items, code)
+	if time.Now().After(c.ExpireAt) {
+		return AuthCode{}, false, nil
+	}
+	return c, true, nil
+}
@@ -0,0 +1,316 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"gpm/app/model"
+	"gpm/app/service/casbin_service"
+	"gpm/app/service/keyring"
+	"gpm/app/service/session"
+	"gpm/global"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Error OAuth2 标准错误，Code 取值见 RFC 6749 5.2
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+func newError(code, description string) *Error {
+	return &Error{Code: code, Description: description}
+}
+
+const accessTokenType = "oauth_access"
+
+// AccessClaims 下发给客户端的访问令牌，用于调用 userinfo
+type AccessClaims struct {
+	Tenant   string `json:"tenant"`
+	ClientID string `json:"client_id"`
+	Scope    string `json:"scope"`
+	Type     string `json:"type"`
+	Sid      string `json:"sid,omitempty"` // 授权时 gpm 会话，会话吊销或用户禁用后令牌随即失效
+	jwt.RegisteredClaims
+}
+
+// IDClaims id_token 声明，包含 gpm 的租户与角色
+type IDClaims struct {
+	Nonce    string   `json:"nonce,omitempty"`
+	AuthTime int64    `json:"auth_time"`
+	Email    string   `json:"email,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Tenant   string   `json:"tenant"`
+	Roles    []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+// AuthorizeReq 授权请求参数
+type AuthorizeReq struct {
+	ResponseType        string `form:"response_type"`
+	ClientID            string `form:"client_id"`
+	RedirectURI         string `form:"redirect_uri"`
+	Scope               string `form:"scope"`
+	State               string `form:"state"`
+	Nonce               string `form:"nonce"`
+	CodeChallenge       string `form:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method"`
+}
+
+// TokenReq 令牌请求参数
+type TokenReq struct {
+	GrantType    string `form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	CodeVerifier string `form:"code_verifier"`
+}
+
+// TokenRes 令牌响应
+type TokenRes struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	IDToken     string `json:"id_token"`
+	Scope       string `json:"scope"`
+}
+
+func tokenExpire() time.Duration {
+	if global.Config.OidcServer.TokenExpire > 0 {
+		return time.Duration(global.Config.OidcServer.TokenExpire) * time.Second
+	}
+	return time.Hour
+}
+
+// CheckAuthorize 校验客户端与回调地址，失败时不能重定向回客户端
+func CheckAuthorize(ctx context.Context, cr AuthorizeReq) (*model.OAuthClient, error) {
+	client, err := GetClient(ctx, cr.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if err = CheckRedirectURI(client, cr.RedirectURI); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Authorize 为已登录用户签发授权码，返回重定向地址；参数错误也通过重定向告知客户端
+func Authorize(ctx context.Context, client *model.OAuthClient, cr AuthorizeReq, userID, sid string) (string, error) {
+	redirect, err := url.Parse(cr.RedirectURI)
+	if err != nil {
+		return "", err
+	}
+	query := redirect.Query()
+	if cr.State != "" {
+		query.Set("state", cr.State)
+	}
+	fail := func(e *Error) (string, error) {
+		query.Set("error", e.Code)
+		query.Set("error_description", e.Description)
+		redirect.RawQuery = query.Encode()
+		return redirect.String(), nil
+	}
+	if cr.ResponseType != "code" {
+		return fail(newError("unsupported_response_type", "仅支持授权码模式"))
+	}
+	if !slices.Contains(strings.Fields(cr.Scope), "openid") {
+		return fail(newError("invalid_scope", "scope 必须包含 openid"))
+	}
+	if cr.CodeChallenge == "" && client.Public {
+		return fail(newError("invalid_request", "公开客户端必须使用 PKCE"))
+	}
+	if cr.CodeChallenge != "" && cr.CodeChallengeMethod != "S256" {
+		return fail(newError("invalid_request", "code_challenge_method 仅支持 S256"))
+	}
+	// id_token 携带客户端所属租户，只签发给该租户的成员
+	if !casbin_service.IsMember(userID, client.TenantID) {
+		return fail(newError("access_denied", "用户不属于该应用所在租户"))
+	}
+	code, err := randomString(32)
+	if err != nil {
+		return "", err
+	}
+	expire := global.Config.OidcServer.CodeExpire
+	if expire <= 0 {
+		expire = 60
+	}
+	now := time.Now()
+	err = Codes.Save(code, AuthCode{
+		ClientID:            client.ClientID,
+		UserID:              userID,
+		Sid:                 sid,
+		Tenant:              client.TenantID,
+		RedirectURI:         cr.RedirectURI,
+		Scope:               cr.Scope,
+		Nonce:               cr.Nonce,
+		CodeChallenge:       cr.CodeChallenge,
+		CodeChallengeMethod: cr.CodeChallengeMethod,
+		AuthTime:            now,
+		ExpireAt:            now.Add(time.Duration(expire) * time.Second),
+	})
+	if err != nil {
+		return "", err
+	}
+	query.Set("code", code)
+	redirect.RawQuery = query.Encode()
+	return redirect.String(), nil
+}
+
+// Exchange 使用授权码换取访问令牌与 id_token
+func Exchange(ctx context.Context, issuer string, cr TokenReq) (*TokenRes, error) {
+	if cr.GrantType != "authorization_code" {
+		return nil, newError("unsupported_grant_type", "仅支持 authorization_code")
+	}
+	client, err := GetClient(ctx, cr.ClientID)
+	if err != nil {
+		return nil, newError("invalid_client", err.Error())
+	}
+	if err = Authenticate(client, cr.ClientSecret); err != nil {
+		return nil, newError("invalid_client", err.Error())
+	}
+	code, ok, err := Codes.Take(cr.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || code.ClientID != client.ClientID || code.RedirectURI != cr.RedirectURI {
+		return nil, newError("invalid_grant", "授权码无效或已过期")
+	}
+	if code.CodeChallenge != "" {
+		sum := sha256.Sum256([]byte(cr.CodeVerifier))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != code.CodeChallenge {
+			return nil, newError("invalid_grant", "code_verifier 校验失败")
+		}
+	}
+	var user model.User
+	if err = global.DB.WithContext(ctx).Where("id = ?", code.UserID).Take(&user).Error; err != nil {
+		return nil, newError("invalid_grant", "用户不存在")
+	}
+	if err = session.Check(ctx, code.Sid, user.ID); err != nil {
+		return nil, newError("invalid_grant", err.Error())
+	}
+
+	now := time.Now()
+	expire := tokenExpire()
+	registered := jwt.RegisteredClaims{
+		Issuer:    issuer,
+		Subject:   user.ID,
+		Audience:  jwt.ClaimStrings{client.ClientID},
+		ExpiresAt: jwt.NewNumericDate(now.Add(expire)),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+	accessToken, err := keyring.Sign(ctx, AccessClaims{
+		Tenant:           code.Tenant,
+		ClientID:         client.ClientID,
+		Scope:            code.Scope,
+		Type:             accessTokenType,
+		Sid:              code.Sid,
+		RegisteredClaims: registered,
+	})
+	if err != nil {
+		return nil, err
+	}
+	idClaims := IDClaims{
+		Nonce:            code.Nonce,
+		AuthTime:         code.AuthTime.Unix(),
+		Tenant:           code.Tenant,
+		Roles:            global.CasbinEnforcer.GetRolesForUserInDomain(casbin_service.UserSubject(user.ID), code.Tenant),
+		RegisteredClaims: registered,
+	}
+	scopes := strings.Fields(code.Scope)
+	if slices.Contains(scopes, "email") {
+		idClaims.Email = user.Email
+	}
+	if slices.Contains(scopes, "profile") {
+		idClaims.Name = user.Nickname
+	}
+	idToken, err := keyring.Sign(ctx, idClaims)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenRes{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(expire.Seconds()),
+		IDToken:     idToken,
+		Scope:       code.Scope,
+	}, nil
+}
+
+// ParseAccessToken 校验 gpm 签发给客户端的访问令牌
+func ParseAccessToken(ctx context.Context, issuer, raw string) (*AccessClaims, error) {
+	var claims AccessClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, keyring.Keyfunc(ctx), jwt.WithIssuer(issuer))
+	if err != nil {
+		return nil, newError("invalid_token", err.Error())
+	}
+	if claims.Type != accessTokenType {
+		return nil, newError("invalid_token", "令牌类型错误")
+	}
+	return &claims, nil
+}
+
+// Userinfo 返回访问令牌对应用户的声明
+func Userinfo(ctx context.Context, claims *AccessClaims) (map[string]any, error) {
+	if err := session.Check(ctx, claims.Sid, claims.Subject); err != nil {
+		return nil, newError("invalid_token", err.Error())
+	}
+	var user model.User
+	err := global.DB.WithContext(ctx).Where("id = ?", claims.Subject).Take(&user).Error
+	if err != nil {
+		return nil, newError("invalid_token", "用户不存在")
+	}
+	info := map[string]any{
+		"sub":    user.ID,
+		"tenant": claims.Tenant,
+		"roles":  global.CasbinEnforcer.GetRolesForUserInDomain(casbin_service.UserSubject(user.ID), claims.Tenant),
+	}
+	scopes := strings.Fields(claims.Scope)
+	if slices.Contains(scopes, "email") {
+		info["email"] = user.Email
+	}
+	if slices.Contains(scopes, "profile") {
+		info["name"] = user.Nickname
+		info["preferred_username"] = user.Username
+		info["picture"] = user.Avatar
+	}
+	return info, nil
+}
+
+// Discovery 发现文档
+func Discovery(issuer string) map[string]any {
+	return map[string]any{
+		"issuer":                                issuer,
+		"authorization_endpoint":                issuer + "/oauth/authorize",
+		"token_endpoint":                        issuer + "/oauth/token",
+		"userinfo_endpoint":                     issuer + "/oauth/userinfo",
+		"jwks_uri":                              issuer + "/.well-known/jwks.json",
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 []string{"authorization_code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{keyring.Alg()},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"claims_supported":                      []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "email", "name", "tenant", "roles"},
+	}
+}
+
+// AsError 提取 OAuth2 标准错误，其他错误按 server_error 处理
+func AsError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return newError("server_error", err.Error())
+}
@@ -0,0 +1,194 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"gpm/app/model"
+	"gpm/app/service/casbin_service"
+	"gpm/common/util"
+	"gpm/global"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrStateInvalid  = errors.New("登录状态无效或已过期")
+	ErrNonceMismatch = errors.New("id_token nonce 不匹配")
+	ErrNotLinked     = errors.New("外部账号未关联本地用户")
+	ErrEmailTaken    = errors.New("邮箱已被本地账号使用，请先登录后关联")
+	ErrEmailMissing  = errors.New("身份提供方未返回邮箱，无法自动开通")
+	ErrUserDisabled  = errors.New("账号已禁用")
+)
+
+// IdentityClaims id_token 中使用的声明
+type IdentityClaims struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// AuthURL 生成授权地址，state、nonce 与 PKCE verifier 保存在服务端
+func AuthURL(ctx context.Context, name string) (string, error) {
+	p, err := GetProvider(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	state, err := randomString(24)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomString(24)
+	if err != nil {
+		return "", err
+	}
+	expire := global.Config.Oidc.StateExpire
+	if expire <= 0 {
+		expire = 600
+	}
+	verifier := oauth2.GenerateVerifier()
+	err = Store.Save(state, LoginState{
+		Provider: name,
+		Verifier: verifier,
+		Nonce:    nonce,
+		ExpireAt: time.Now().Add(time.Duration(expire) * time.Second),
+	})
+	if err != nil {
+		return "", err
+	}
+	return p.OAuth2.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier), oidc.Nonce(nonce)), nil
+}
+
+// Callback 处理授权回调：换取并校验 id_token，返回关联的本地用户与登录租户
+func Callback(ctx context.Context, name, state, code string) (*model.User, string, error) {
+	s, ok, err := Store.Take(state)
+	if err != nil {
+		return nil, "", err
+	}
+	if !ok || s.Provider != name {
+		return nil, "", ErrStateInvalid
+	}
+	p, err := GetProvider(ctx, name)
+	if err != nil {
+		return nil, "", err
+	}
+	token, err := p.OAuth2.Exchange(ctx, code, oauth2.VerifierOption(s.Verifier))
+	if err != nil {
+		return nil, "", fmt.Errorf("授权码换取令牌失败: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, "", errors.New("身份提供方未返回 id_token")
+	}
+	idToken, err := p.Verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, "", fmt.Errorf("id_token 校验失败: %w", err)
+	}
+	if idToken.Nonce != s.Nonce {
+		return nil, "", ErrNonceMismatch
+	}
+	var claims IdentityClaims
+	if err = idToken.Claims(&claims); err != nil {
+		return nil, "", err
+	}
+	user, err := resolveUser(ctx, p, claims)
+	if err != nil {
+		return nil, "", err
+	}
+	// 外部身份登录与密码登录一样受账号状态限制
+	if !user.Status {
+		return nil, "", ErrUserDisabled
+	}
+	return user, p.Conf.Tenant, nil
+}
+
+// resolveUser 按 已关联身份 -> 已验证邮箱 -> 自动开通 的顺序确定本地用户
+func resolveUser(ctx context.Context, p *Provider, claims IdentityClaims) (*model.User, error) {
+	db := global.DB.WithContext(ctx)
+	var identity model.UserIdentity
+	err := db.Preload("User").Where("provider = ? AND subject = ?", p.Conf.Name, claims.Subject).Take(&identity).Error
+	if err == nil {
+		return &identity.User, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var user model.User
+	if claims.Email != "" {
+		err = db.Where("email = ?", claims.Email).Take(&user).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	if user.ID != "" {
+		if !p.Conf.LinkByEmail || !claims.EmailVerified {
+			return nil, ErrEmailTaken
+		}
+		err = db.Create(&model.UserIdentity{UserID: user.ID, Provider: p.Conf.Name, Subject: claims.Subject, Email: claims.Email}).Error
+		if err != nil {
+			return nil, err
+		}
+		return &user, nil
+	}
+	if !p.Conf.AutoProvision {
+		return nil, ErrNotLinked
+	}
+	if claims.Email == "" {
+		return nil, ErrEmailMissing
+	}
+	return provisionUser(ctx, p, claims)
+}
+
+// provisionUser 创建本地用户并授予租户默认角色，本地密码随机生成，只能通过外部身份登录
+func provisionUser(ctx context.Context, p *Provider, claims IdentityClaims) (*model.User, error) {
+	salt := uuid.New().String()
+	password, err := randomString(24)
+	if err != nil {
+		return nil, err
+	}
+	user := model.User{
+		Username: p.Conf.Name + ":" + claims.Subject,
+		Nickname: claims.Name,
+		Email:    claims.Email,
+		Avatar:   claims.Picture,
+		Password: util.Md5([]byte(password + salt)),
+		Salt:     salt,
+		Status:   true,
+		// 身份由外部提供方认证，无需再走邮箱验证
+		VerifiedAt: int(time.Now().Unix()),
+	}
+	err = global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.UserIdentity{UserID: user.ID, Provider: p.Conf.Name, Subject: claims.Subject, Email: claims.Email}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if p.Conf.Tenant != "" && p.Conf.DefaultRole != "" {
+		_, err = global.CasbinEnforcer.AddRoleForUserInDomain(
+			casbin_service.UserSubject(user.ID), casbin_service.RoleSubject(p.Conf.DefaultRole), p.Conf.Tenant)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &user, nil
+}
@@ -0,0 +1,442 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"gpm/app/model"
+	"gpm/app/service/casbin_service"
+	"gpm/conf"
+	"gpm/global"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	mockClientID    = "gpm"
+	mockRedirectURL = "http://gpm.test/gpm/oidc/mock/callback"
+	mockKeyID       = "mock-key"
+)
+
+// mockCode 模拟身份提供方签发的授权码
+type mockCode struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+// mockProvider 基于 httptest 的身份提供方，提供发现文档、JWKS 与令牌端点
+type mockProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]mockCode
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockProvider{t: t, key: key, codes: map[string]mockCode{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", m.discovery)
+	mux.HandleFunc("/jwks", m.jwks)
+	mux.HandleFunc("/token", m.token)
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                m.server.URL,
+		"authorization_endpoint":                m.server.URL + "/authorize",
+		"token_endpoint":                        m.server.URL + "/token",
+		"jwks_uri":                              m.server.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (m *mockProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := m.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": mockKeyID,
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+// token 授权码只能使用一次，code_verifier 必须与授权时的 code_challenge 匹配
+func (m *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+	m.mu.Lock()
+	code, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+	if !ok || r.PostForm.Get("redirect_uri") != mockRedirectURL {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != code.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss": m.server.URL,
+		"aud": mockClientID,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	for k, v := range code.claims {
+		claims[k] = v
+	}
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = mockKeyID
+	raw, err := idToken.SignedString(m.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "mock-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     raw,
+	})
+}
+
+// authorize 模拟用户在身份提供方完成登录：校验授权地址并签发授权码
+// claims 中未指定 nonce 时使用授权地址中的 nonce
+func (m *mockProvider) authorize(authURL string, claims jwt.MapClaims) (state, code string) {
+	m.t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	if !strings.HasPrefix(authURL, m.server.URL+"/authorize?") {
+		m.t.Fatalf("授权地址不正确: %s", authURL)
+	}
+	q := u.Query()
+	for name, want := range map[string]string{
+		"response_type":         "code",
+		"client_id":             mockClientID,
+		"redirect_uri":          mockRedirectURL,
+		"code_challenge_method": "S256",
+	} {
+		if got := q.Get(name); got != want {
+			m.t.Fatalf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if !slices.Contains(strings.Fields(q.Get("scope")), "openid") {
+		m.t.Fatalf("scope 缺少 openid: %q", q.Get("scope"))
+	}
+	for _, name := range []string{"state", "nonce", "code_challenge"} {
+		if q.Get(name) == "" {
+			m.t.Fatalf("授权地址缺少 %s", name)
+		}
+	}
+	if _, ok := claims["nonce"]; !ok {
+		claims["nonce"] = q.Get("nonce")
+	}
+	code = "code-" + q.Get("state")
+	m.mu.Lock()
+	m.codes[code] = mockCode{challenge: q.Get("code_challenge"), claims: claims}
+	m.mu.Unlock()
+	return q.Get("state"), code
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// setup 启动模拟身份提供方，使用临时 SQLite 数据库与内存 Casbin
+func setup(t *testing.T, pc conf.OidcProvider) *mockProvider {
+	t.Helper()
+	m := newMockProvider(t)
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "gpm.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&model.User{}, &model.UserIdentity{}); err != nil {
+		t.Fatal(err)
+	}
+	e, err := casbin.NewSyncedEnforcer("../../../conf/rbac_with_domains_model.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.AddFunction("objMatch", casbin_service.ObjMatchFunc)
+	e.AddFunction("actMatch", casbin_service.ActMatchFunc)
+
+	pc.Name = "mock"
+	pc.Issuer = m.server.URL
+	pc.ClientID = mockClientID
+	pc.ClientSecret = "secret"
+	pc.RedirectURL = mockRedirectURL
+	pc.Scopes = []string{"email", "profile"}
+
+	oldDB, oldConfig, oldEnforcer, oldStore := global.DB, global.Config, global.CasbinEnforcer, Store
+	global.DB = db
+	global.Config = &conf.Config{Oidc: conf.Oidc{Providers: []conf.OidcProvider{pc}}}
+	global.CasbinEnforcer = e
+	Store = NewMemoryStateStore()
+	providers = map[string]*Provider{}
+	t.Cleanup(func() {
+		global.DB, global.Config, global.CasbinEnforcer, Store = oldDB, oldConfig, oldEnforcer, oldStore
+		providers = map[string]*Provider{}
+	})
+	return m
+}
+
+// login 完整走一遍 授权地址 -> 提供方登录 -> 回调
+func (m *mockProvider) login(claims jwt.MapClaims) (*model.User, string, error) {
+	m.t.Helper()
+	ctx := context.Background()
+	authURL, err := AuthURL(ctx, "mock")
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	state, code := m.authorize(authURL, claims)
+	return Callback(ctx, "mock", state, code)
+}
+
+func createUser(t *testing.T, email string, status bool) model.User {
+	t.Helper()
+	user := model.User{Username: email, Email: email, Salt: "salt", Status: status, VerifiedAt: 1}
+	if err := global.DB.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func countIdentities(t *testing.T, userID string) int64 {
+	t.Helper()
+	var count int64
+	if err := global.DB.Model(&model.UserIdentity{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestCallbackAutoProvision(t *testing.T) {
+	m := setup(t, conf.OidcProvider{AutoProvision: true, Tenant: "tenant-1", DefaultRole: "role-1"})
+
+	user, tenant, err := m.login(jwt.MapClaims{"sub": "alice", "email": "alice@example.com", "email_verified": true, "name": "Alice"})
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if tenant != "tenant-1" {
+		t.Errorf("tenant = %q, want tenant-1", tenant)
+	}
+	if user.Email != "alice@example.com" || user.Nickname != "Alice" {
+		t.Errorf("user = %+v", user)
+	}
+	if !user.Status || user.VerifiedAt == 0 {
+		t.Errorf("自动开通的用户应启用且视为已验证: status=%v verifiedAt=%d", user.Status, user.VerifiedAt)
+	}
+	if n := countIdentities(t, user.ID); n != 1 {
+		t.Errorf("identities = %d, want 1", n)
+	}
+	roles := global.CasbinEnforcer.GetRolesForUserInDomain(casbin_service.UserSubject(user.ID), "tenant-1")
+	if !slices.Contains(roles, casbin_service.RoleSubject("role-1")) {
+		t.Errorf("roles = %v, want default role", roles)
+	}
+
+	// 再次登录按已关联身份找到同一用户，即使提供方返回的邮箱已变化
+	again, _, err := m.login(jwt.MapClaims{"sub": "alice", "email": "alice@new.example.com", "email_verified": true})
+	if err != nil {
+		t.Fatalf("second Callback: %v", err)
+	}
+	if again.ID != user.ID {
+		t.Errorf("second login user = %s, want %s", again.ID, user.ID)
+	}
+	var users int64
+	global.DB.Model(&model.User{}).Count(&users)
+	if users != 1 {
+		t.Errorf("users = %d, want 1", users)
+	}
+}
+
+func TestCallbackStateIsSingleUse(t *testing.T) {
+	m := setup(t, conf.OidcProvider{AutoProvision: true})
+	ctx := context.Background()
+
+	authURL, err := AuthURL(ctx, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code := m.authorize(authURL, jwt.MapClaims{"sub": "bob", "email": "bob@example.com"})
+	if _, _, err = Callback(ctx, "mock", state, code); err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if _, _, err = Callback(ctx, "mock", state, code); !errors.Is(err, ErrStateInvalid) {
+		t.Errorf("重复使用 state: err = %v, want ErrStateInvalid", err)
+	}
+	if _, _, err = Callback(ctx, "mock", "unknown", code); !errors.Is(err, ErrStateInvalid) {
+		t.Errorf("未知 state: err = %v, want ErrStateInvalid", err)
+	}
+
+	// state 与发起登录的提供方绑定
+	authURL, err = AuthURL(ctx, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code = m.authorize(authURL, jwt.MapClaims{"sub": "bob"})
+	if _, _, err = Callback(ctx, "other", state, code); !errors.Is(err, ErrStateInvalid) {
+		t.Errorf("其他提供方的 state: err = %v, want ErrStateInvalid", err)
+	}
+}
+
+func TestCallbackStateExpired(t *testing.T) {
+	m := setup(t, conf.OidcProvider{AutoProvision: true})
+	ctx := context.Background()
+
+	authURL, err := AuthURL(ctx, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code := m.authorize(authURL, jwt.MapClaims{"sub": "bob", "email": "bob@example.com"})
+	s, ok, _ := Store.Take(state)
+	if !ok {
+		t.Fatal("state 未保存")
+	}
+	s.ExpireAt = time.Now().Add(-time.Second)
+	_ = Store.Save(state, s)
+	if _, _, err = Callback(ctx, "mock", state, code); !errors.Is(err, ErrStateInvalid) {
+		t.Errorf("err = %v, want ErrStateInvalid", err)
+	}
+}
+
+func TestCallbackNonceMismatch(t *testing.T) {
+	m := setup(t, conf.OidcProvider{AutoProvision: true})
+
+	_, _, err := m.login(jwt.MapClaims{"sub": "carol", "email": "carol@example.com", "nonce": "forged"})
+	if !errors.Is(err, ErrNonceMismatch) {
+		t.Fatalf("err = %v, want ErrNonceMismatch", err)
+	}
+	var users int64
+	global.DB.Model(&model.User{}).Count(&users)
+	if users != 0 {
+		t.Errorf("nonce 不匹配时不应开通用户, users = %d", users)
+	}
+}
+
+func TestCallbackPKCE(t *testing.T) {
+	m := setup(t, conf.OidcProvider{AutoProvision: true})
+	ctx := context.Background()
+
+	authURL, err := AuthURL(ctx, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code := m.authorize(authURL, jwt.MapClaims{"sub": "dave", "email": "dave@example.com"})
+	// 授权码被其他客户端截获后换取令牌：提供方记录的 code_challenge 与服务端保存的 verifier 不匹配
+	m.mu.Lock()
+	c := m.codes[code]
+	c.challenge = "intercepted"
+	m.codes[code] = c
+	m.mu.Unlock()
+
+	if _, _, err = Callback(ctx, "mock", state, code); err == nil {
+		t.Fatal("PKCE 校验失败时应返回错误")
+	}
+}
+
+func TestCallbackLinkByEmail(t *testing.T) {
+	tests := []struct {
+		name        string
+		linkByEmail bool
+		verified    bool
+		wantErr     error
+	}{
+		{name: "linked", linkByEmail: true, verified: true},
+		{name: "link disabled", linkByEmail: false, verified: true, wantErr: ErrEmailTaken},
+		{name: "email unverified", linkByEmail: true, verified: false, wantErr: ErrEmailTaken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := setup(t, conf.OidcProvider{LinkByEmail: tt.linkByEmail, AutoProvision: true})
+			existing := createUser(t, "erin@example.com", true)
+
+			user, _, err := m.login(jwt.MapClaims{"sub": "erin", "email": "erin@example.com", "email_verified": tt.verified})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if n := countIdentities(t, existing.ID); n != 0 {
+					t.Errorf("identities = %d, want 0", n)
+				}
+				return
+			}
+			if user.ID != existing.ID {
+				t.Errorf("user = %s, want existing %s", user.ID, existing.ID)
+			}
+			if n := countIdentities(t, existing.ID); n != 1 {
+				t.Errorf("identities = %d, want 1", n)
+			}
+		})
+	}
+}
+
+func TestCallbackWithoutProvision(t *testing.T) {
+	m := setup(t, conf.OidcProvider{})
+	if _, _, err := m.login(jwt.MapClaims{"sub": "frank", "email": "frank@example.com"}); !errors.Is(err, ErrNotLinked) {
+		t.Errorf("err = %v, want ErrNotLinked", err)
+	}
+
+	m = setup(t, conf.OidcProvider{AutoProvision: true})
+	if _, _, err := m.login(jwt.MapClaims{"sub": "frank"}); !errors.Is(err, ErrEmailMissing) {
+		t.Errorf("err = %v, want ErrEmailMissing", err)
+	}
+}
+
+func TestCallbackDisabledUser(t *testing.T) {
+	m := setup(t, conf.OidcProvider{AutoProvision: true})
+	user := createUser(t, "grace@example.com", false)
+	identity := model.UserIdentity{UserID: user.ID, Provider: "mock", Subject: "grace"}
+	if err := global.DB.Create(&identity).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := m.login(jwt.MapClaims{"sub": "grace", "email": "grace@example.com"}); !errors.Is(err, ErrUserDisabled) {
+		t.Errorf("err = %v, want ErrUserDisabled", err)
+	}
+}
@@ -0,0 +1,67 @@
+// package oidc: 外部 OIDC 身份提供方登录（授权码 + PKCE）
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gpm/conf"
+	"gpm/global"
+	"slices"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+var ErrProviderNotFound = errors.New("身份提供方不存在")
+
+// Provider 已完成发现的身份提供方
+type Provider struct {
+	Conf     conf.OidcProvider
+	OAuth2   oauth2.Config
+	Verifier *oidc.IDTokenVerifier
+}
+
+var (
+	providerMu sync.Mutex
+	providers  = map[string]*Provider{}
+)
+
+// GetProvider 按名称获取身份提供方，首次使用时读取发现文档，失败不缓存以便下次重试
+func GetProvider(ctx context.Context, name string) (*Provider, error) {
+	providerMu.Lock()
+	defer providerMu.Unlock()
+	if p, ok := providers[name]; ok {
+		return p, nil
+	}
+	i := slices.IndexFunc(global.Config.Oidc.Providers, func(p conf.OidcProvider) bool { return p.Name == name })
+	if i < 0 {
+		return nil, ErrProviderNotFound
+	}
+	pc := global.Config.Oidc.Providers[i]
+	// 发现文档与 JWKS 会在后续请求中复用，不随单次请求取消
+	op, err := oidc.NewProvider(context.WithoutCancel(ctx), pc.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("读取身份提供方 %s 发现文档失败: %w", name, err)
+	}
+	scopes := []string{oidc.ScopeOpenID}
+	for _, scope := range pc.Scopes {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	p := &Provider{
+		Conf: pc,
+		OAuth2: oauth2.Config{
+			ClientID:     pc.ClientID,
+			ClientSecret: pc.ClientSecret,
+			RedirectURL:  pc.RedirectURL,
+			Endpoint:     op.Endpoint(),
+			Scopes:       scopes,
+		},
+		Verifier: op.Verifier(&oidc.Config{ClientID: pc.ClientID}),
+	}
+	providers[name] = p
+	return p, nil
+}
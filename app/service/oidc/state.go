@@ -0,0 +1,61 @@
+package oidc
+
+import (
+	"sync"
+	"time"
+)
+
+// LoginState 登录发起时保存的状态，回调时一次性取出
+type LoginState struct {
+	Provider string
+	Verifier string // PKCE code_verifier
+	Nonce    string
+	ExpireAt time.Time
+}
+
+// StateStore 登录状态存储，多实例部署时可替换为共享存储实现
+type StateStore interface {
+	Save(state string, s LoginState) error
+	// Take 取出并删除状态，不存在或已过期时 ok 为 false
+	Take(state string) (s LoginState, ok bool, err error)
+}
+
+// Store 当前使用的登录状态存储
+var Store StateStore = NewMemoryStateStore()
+
+// MemoryStateStore 单实例内存存储
+type MemoryStateStore struct {
+	mu    sync.Mutex
+	items map[string]LoginState
+}
+
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{items: map[string]LoginState{}}
+}
+
+func (m *MemoryStateStore) Save(state string, s LoginState) error {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, item := range m.items {
+		if now.After(item.ExpireAt) {
+			delete(m.items, k)
+		}
+	}
+	m.items[state] = s
+	return nil
+}
+
+func (m *MemoryStateStore) Take(state string) (LoginState, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.items[state]
+	if !ok {
+		return LoginState{}, false, nil
+	}
+	delete(m.items, state)
+	if time.Now().After(s.ExpireAt) {
+		return LoginState{}, false, nil
+	}
+	return s, true, nil
+}
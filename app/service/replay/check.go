@@ -0,0 +1,63 @@
+package replay
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// DefaultWindow 未配置时允许的客户端时钟偏差
+const DefaultWindow = 300 * time.Second
+
+var (
+	ErrTimestampInvalid = errors.New("timestamp格式错误")
+	ErrTimestampExpired = errors.New("请求时间超出允许范围")
+	ErrNonceInvalid     = errors.New("nonce格式错误")
+	ErrReplay           = errors.New("请求重复提交")
+)
+
+// CheckTimestamp 校验请求时间戳（秒或毫秒）是否在 window 范围内
+func CheckTimestamp(timestamp string, window time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrTimestampInvalid
+	}
+	var t time.Time
+	if ts > 1e12 {
+		t = time.UnixMilli(ts)
+	} else {
+		t = time.Unix(ts, 0)
+	}
+	if d := time.Since(t); d > window || d < -window {
+		return ErrTimestampExpired
+	}
+	return nil
+}
+
+// CheckNonce 记录 nonce，同一租户下窗口期内重复出现视为重放
+// ttl 取两倍窗口，覆盖客户端时钟前后偏差的全部有效期
+func CheckNonce(tenant, nonce string, window time.Duration) error {
+	if !validNonce(nonce) {
+		return ErrNonceInvalid
+	}
+	seen, err := Store.CheckAndSet(tenant+":"+nonce, 2*window)
+	if err != nil {
+		return err
+	}
+	if seen {
+		return ErrReplay
+	}
+	return nil
+}
+
+func validNonce(nonce string) bool {
+	if len(nonce) < 8 || len(nonce) > 64 {
+		return false
+	}
+	for _, c := range nonce {
+		if !((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package replay
+
+import (
+	"sync"
+	"time"
+)
+
+// NonceStore 已使用 nonce 的存储，多实例部署时可替换为共享存储实现
+type NonceStore interface {
+	// CheckAndSet 原子地检查并记录 key，key 已存在且未过期时返回 true
+	CheckAndSet(key string, ttl time.Duration) (seen bool, err error)
+}
+
+// Store 当前使用的 nonce 存储
+var Store NonceStore = NewMemoryStore(time.Minute)
+
+// MemoryStore 单实例内存存储，过期条目定期清理
+type MemoryStore struct {
+	mu        sync.Mutex
+	items     map[string]time.Time // key -> 过期时间
+	interval  time.Duration
+	lastSweep time.Time
+}
+
+// NewMemoryStore 创建内存存储，interval 为过期条目的清理间隔
+func NewMemoryStore(interval time.Duration) *MemoryStore {
+	return &MemoryStore{
+		items:     map[string]time.Time{},
+		interval:  interval,
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryStore) CheckAndSet(key string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastSweep) > s.interval {
+		for k, expire := range s.items {
+			if now.After(expire) {
+				delete(s.items, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	if expire, ok := s.items[key]; ok && now.Before(expire) {
+		return true, nil
+	}
+	s.items[key] = now.Add(ttl)
+	return false, nil
+}
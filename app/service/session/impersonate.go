@@ -0,0 +1,84 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"gpm/app/model"
+	"gpm/app/service/casbin_service"
+	"gpm/app/service/jwt"
+	"gpm/global"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReasonImpersonateEnd 结束模拟登录
+const ReasonImpersonateEnd = "impersonate_end"
+
+var (
+	ErrImpersonateSelf   = errors.New("不能模拟自己")
+	ErrImpersonateNested = errors.New("模拟登录期间不能再次模拟")
+	ErrNotImpersonating  = errors.New("当前不在模拟登录中")
+	ErrImpersonateMember = errors.New("目标用户不属于当前租户")
+	ErrImpersonateHigher = errors.New("不能模拟权限高于自己的用户")
+)
+
+// ImpersonateRes 模拟登录令牌，到期后不可刷新
+type ImpersonateRes struct {
+	AccessToken string `json:"accessToken"`
+	ExpireAt    int    `json:"expireAt"`
+}
+
+// Impersonate 以目标用户身份创建短期会话，会话与令牌均记录真实操作人
+func Impersonate(ctx context.Context, client Client, tenant, userID, actorID string) (*ImpersonateRes, error) {
+	if userID == actorID {
+		return nil, ErrImpersonateSelf
+	}
+	var user model.User
+	err := global.DB.WithContext(ctx).Where("id = ?", userID).Take(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("账号不存在")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !casbin_service.IsMember(userID, tenant) {
+		return nil, ErrImpersonateMember
+	}
+	covered, err := casbin_service.Covers(casbin_service.UserSubject(actorID), casbin_service.UserSubject(userID), tenant)
+	if err != nil {
+		return nil, err
+	}
+	if !covered {
+		return nil, ErrImpersonateHigher
+	}
+	expire := jwt.ImpersonateExpire
+	if n := global.Config.Jwt.ImpersonateExpire; n > 0 {
+		expire = time.Duration(n) * time.Second
+	}
+	session := model.UserSession{
+		UserID:    userID,
+		TenantID:  tenant,
+		Device:    client.Device,
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+		ActorID:   actorID,
+		ExpireAt:  int(time.Now().Add(expire).Unix()),
+	}
+	if err = global.DB.WithContext(ctx).Create(&session).Error; err != nil {
+		return nil, err
+	}
+	token, err := jwt.NewJWT().GenImpersonateToken(tenant, userID, actorID, session.ID, expire)
+	if err != nil {
+		return nil, err
+	}
+	return &ImpersonateRes{AccessToken: token, ExpireAt: session.ExpireAt}, nil
+}
+
+// EndImpersonate 结束模拟登录，吊销对应会话
+func EndImpersonate(ctx context.Context, userID, sid string) error {
+	if sid == "" {
+		return ErrNotImpersonating
+	}
+	return Revoke(ctx, userID, sid, ReasonImpersonateEnd)
+}
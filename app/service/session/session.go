@@ -0,0 +1,292 @@
+// package session: 登录会话，记录每次签发的令牌对，支持查看与吊销
+package session
+
+import (
+	"context"
+	"errors"
+	"gpm/app/model"
+	"gpm/app/service/jwt"
+	"gpm/app/service/mfa"
+	"gpm/global"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HeaderDevice 客户端可通过该请求头上报设备名称
+const HeaderDevice = "device"
+
+// 吊销原因
+const (
+	ReasonLogout   = "logout"
+	ReasonAdmin    = "admin"
+	ReasonLimit    = "limit"
+	ReasonPassword = "password"
+	ReasonDisabled = "disabled"
+)
+
+var (
+	ErrSessionNotFound = errors.New("会话不存在")
+	ErrSessionRevoked  = errors.New("会话已失效，请重新登录")
+	ErrUserDisabled    = errors.New("账号已禁用")
+	ErrMfaRequired     = errors.New("当前租户要求多因素认证，请登录该租户并完成验证")
+	ErrTenantMismatch  = errors.New("模拟登录令牌只能访问发起模拟的租户")
+)
+
+// Client 发起登录的客户端信息
+type Client struct {
+	Device    string
+	UserAgent string
+	IP        string
+}
+
+// ClientFrom 从请求中提取客户端信息
+func ClientFrom(c *gin.Context) Client {
+	return Client{
+		Device:    truncate(c.GetHeader(HeaderDevice), 128),
+		UserAgent: truncate(c.Request.UserAgent(), 512),
+		IP:        c.ClientIP(),
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// Issue 创建会话并签发令牌对，超出租户并发上限时吊销最早的会话
+// mfaPassed 表示本次登录已完成多因素认证，会话访问要求 MFA 的租户时据此放行
+func Issue(ctx context.Context, client Client, tenant, userID string, mfaPassed bool) (*jwt.TokenPair, error) {
+	now := time.Now()
+	session := model.UserSession{
+		UserID:    userID,
+		TenantID:  tenant,
+		Device:    client.Device,
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+		ExpireAt:  int(now.Add(time.Duration(global.Config.Jwt.RefreshExpire) * time.Second).Unix()),
+	}
+	if mfaPassed {
+		session.MfaAt = int(now.Unix())
+	}
+	err := global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&session).Error; err != nil {
+			return err
+		}
+		limit := global.Config.Session.Limit(tenant)
+		if limit <= 0 {
+			return nil
+		}
+		var stale []model.UserSession
+		err := active(tx, userID).Where("tenant_id = ?", tenant).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Order("create_at desc").Offset(limit).Find(&stale).Error
+		if err != nil {
+			return err
+		}
+		return revoke(tx, stale, ReasonLimit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jwt.NewJWT().GenPairToken(tenant, userID, session.ID)
+}
+
+// Refresh 使用刷新令牌续期会话
+func Refresh(ctx context.Context, refreshToken string) (*jwt.TokenPair, error) {
+	j := jwt.NewJWT()
+	claims, err := j.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	var session model.UserSession
+	err = global.DB.WithContext(ctx).Where("id = ? AND user_id = ?", claims.Sid, claims.Id).Take(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSessionRevoked
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.RevokedAt > 0 {
+		return nil, ErrSessionRevoked
+	}
+	pair, err := j.RefreshTokens(refreshToken, session.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	updates := map[string]any{"last_refresh_at": int(now.Unix())}
+	if pair.RefreshToken != "" {
+		updates["expire_at"] = int(now.Add(time.Duration(global.Config.Jwt.RefreshExpire) * time.Second).Unix())
+	}
+	if err = global.DB.WithContext(ctx).Model(&session).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// List 用户当前有效的会话，按创建时间倒序
+func List(ctx context.Context, userID string) ([]model.UserSession, error) {
+	var list []model.UserSession
+	err := active(global.DB.WithContext(ctx), userID).Order("create_at desc").Find(&list).Error
+	return list, err
+}
+
+// ListInTenant 用户在租户内当前有效的会话，供租户管理员查看
+func ListInTenant(ctx context.Context, userID, tenant string) ([]model.UserSession, error) {
+	var list []model.UserSession
+	err := active(global.DB.WithContext(ctx), userID).Where("tenant_id = ?", tenant).Order("create_at desc").Find(&list).Error
+	return list, err
+}
+
+// RevokeInTenant 吊销用户在租户内的会话，id 为空时吊销该租户下的全部会话
+func RevokeInTenant(ctx context.Context, userID, tenant, id, reason string) error {
+	return global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := active(tx, userID).Where("tenant_id = ?", tenant)
+		if id != "" {
+			query = query.Where("id = ?", id)
+		}
+		var list []model.UserSession
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if id != "" && len(list) == 0 {
+			return ErrSessionNotFound
+		}
+		return revoke(tx, list, reason)
+	})
+}
+
+// Revoke 吊销用户的指定会话
+func Revoke(ctx context.Context, userID, id, reason string) error {
+	return global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var list []model.UserSession
+		if err := active(tx, userID).Where("id = ?", id).Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return ErrSessionNotFound
+		}
+		return revoke(tx, list, reason)
+	})
+}
+
+// RevokeAll 吊销用户的全部会话，except 非空时保留该会话（通常为当前会话）
+func RevokeAll(ctx context.Context, userID, except, reason string) error {
+	return global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := active(tx, userID)
+		if except != "" {
+			query = query.Where("id <> ?", except)
+		}
+		var list []model.UserSession
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		return revoke(tx, list, reason)
+	})
+}
+
+// Revoked 判断会话是否已被拉黑
+func Revoked(ctx context.Context, sid string) (bool, error) {
+	var count int64
+	err := global.DB.WithContext(ctx).Model(&model.TokenBlack{}).
+		Where("token_uuid = ? AND stop_time > ?", sid, time.Now().Unix()).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Check 校验令牌所属会话未被吊销且用户仍处于启用状态
+// 访问令牌、OAuth 授权码与下游应用令牌都经此校验，sid 为空时只检查用户状态
+func Check(ctx context.Context, sid, userID string) error {
+	if sid != "" {
+		revoked, err := Revoked(ctx, sid)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return ErrSessionRevoked
+		}
+	}
+	var user model.User
+	err := global.DB.WithContext(ctx).Select("id", "status").Where("id = ?", userID).Take(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUserDisabled
+	}
+	if err != nil {
+		return err
+	}
+	if !user.Status {
+		return ErrUserDisabled
+	}
+	return nil
+}
+
+// CheckTenant 校验会话能否访问请求的租户
+// 令牌不绑定租户，登录时只按登录租户判定 MFA，因此每次请求按请求租户的 MFA 策略重新判定
+// 模拟会话的成员与权限校验只在发起模拟的租户进行，只能访问该租户
+func CheckTenant(ctx context.Context, sid, userID, tenant string) error {
+	if tenant == "" {
+		return nil
+	}
+	var session model.UserSession
+	if sid != "" {
+		err := global.DB.WithContext(ctx).Select("id", "tenant_id", "actor_id", "mfa_at").
+			Where("id = ? AND user_id = ?", sid, userID).Take(&session).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionRevoked
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if session.ActorID != "" {
+		if session.TenantID != tenant {
+			return ErrTenantMismatch
+		}
+		// 操作人发起模拟时已按该租户的策略通过校验
+		return nil
+	}
+	if session.MfaAt > 0 {
+		return nil
+	}
+	required, err := mfa.Required(ctx, tenant, userID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrMfaRequired
+	}
+	return nil
+}
+
+func active(db *gorm.DB, userID string) *gorm.DB {
+	return db.Model(&model.UserSession{}).
+		Where("user_id = ? AND revoked_at = 0 AND expire_at > ?", userID, time.Now().Unix())
+}
+
+// revoke 标记会话失效，并将会话标识写入令牌黑名单直至会话过期，令牌携带的 sid 命中即拒绝
+func revoke(tx *gorm.DB, list []model.UserSession, reason string) error {
+	if len(list) == 0 {
+		return nil
+	}
+	now := int(time.Now().Unix())
+	ids := make([]string, 0, len(list))
+	blacks := make([]model.TokenBlack, 0, len(list))
+	for _, s := range list {
+		ids = append(ids, s.ID)
+		blacks = append(blacks, model.TokenBlack{
+			TokenUuid: s.ID,
+			Reason:    reason,
+			StarTime:  now,
+			StopTime:  s.ExpireAt,
+		})
+	}
+	if err := tx.Model(&model.UserSession{}).Where("id IN ?", ids).Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+	return tx.Create(&blacks).Error
+}
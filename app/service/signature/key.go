@@ -0,0 +1,124 @@
+package signature
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"gpm/app/model"
+	"gpm/global"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrKeyNotFound = errors.New("签名密钥不存在")
+	ErrKeyRevoked  = errors.New("签名密钥已吊销")
+	ErrKeyExpired  = errors.New("签名密钥已过期")
+)
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func newKeyID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "gk_" + hex.EncodeToString(b), nil
+}
+
+// IssueKey 为租户签发新的签名密钥，expireAt 为 0 表示永不过期
+// 返回的 Secret 只在签发时可见
+func IssueKey(ctx context.Context, tenantID, remark string, expireAt int) (*model.TenantSignKey, error) {
+	return issueKey(global.DB.WithContext(ctx), tenantID, remark, expireAt)
+}
+
+func issueKey(db *gorm.DB, tenantID, remark string, expireAt int) (*model.TenantSignKey, error) {
+	keyID, err := newKeyID()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+	key := model.TenantSignKey{
+		TenantID: tenantID,
+		KeyID:    keyID,
+		Secret:   secret,
+		Remark:   remark,
+		Status:   true,
+		ExpireAt: expireAt,
+	}
+	if err = db.Create(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RevokeKey 吊销密钥，grace 大于 0 时仅设置过期时间，保留宽限期供客户端切换
+func RevokeKey(ctx context.Context, tenantID, keyID string, grace time.Duration) error {
+	now := time.Now()
+	query := global.DB.WithContext(ctx).Model(&model.TenantSignKey{}).
+		Where("tenant_id = ? AND key_id = ? AND status = ?", tenantID, keyID, true)
+	var result *gorm.DB
+	if grace > 0 {
+		result = query.Update("expire_at", int(now.Add(grace).Unix()))
+	} else {
+		result = query.Updates(map[string]any{"status": false, "revoked_at": int(now.Unix())})
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrKeyNotFound
+	}
+	return nil
+}
+
+// RotateKey 签发新密钥，并让租户其余有效密钥在宽限期后过期
+func RotateKey(ctx context.Context, tenantID, remark string, grace time.Duration) (*model.TenantSignKey, error) {
+	var key *model.TenantSignKey
+	err := global.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		expireAt := int(time.Now().Add(grace).Unix())
+		err := tx.Model(&model.TenantSignKey{}).
+			Where("tenant_id = ? AND status = ? AND (expire_at = 0 OR expire_at > ?)", tenantID, true, expireAt).
+			Update("expire_at", expireAt).Error
+		if err != nil {
+			return err
+		}
+		key, err = issueKey(tx, tenantID, remark, 0)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ActiveKey 查找租户下可用于验签的密钥
+func ActiveKey(ctx context.Context, tenantID, keyID string) (*model.TenantSignKey, error) {
+	var key model.TenantSignKey
+	err := global.DB.WithContext(ctx).Where("tenant_id = ? AND key_id = ?", tenantID, keyID).Take(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !key.Status {
+		return nil, ErrKeyRevoked
+	}
+	if key.ExpireAt > 0 && int64(key.ExpireAt) <= time.Now().Unix() {
+		return nil, ErrKeyExpired
+	}
+	return &key, nil
+}
@@ -0,0 +1,102 @@
+package telemetry
+
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName 埋点名称
+const ScopeName = "gpm"
+
+// 指标实例，未启用 OpenTelemetry 时为空实现
+var (
+	httpRequests metric.Int64Counter
+	httpErrors   metric.Int64Counter
+	httpDuration metric.Float64Histogram
+)
+
+// Tracer 返回 gpm 的 tracer，未启用时为空实现
+func Tracer() trace.Tracer {
+	return otel.Tracer(ScopeName)
+}
+
+func meter() metric.Meter {
+	return otel.Meter(ScopeName)
+}
+
+// InitInstruments 在设置全局 MeterProvider 之后创建指标
+func InitInstruments() error {
+	var err error
+	m := meter()
+	if httpRequests, err = m.Int64Counter("http.server.requests",
+		metric.WithDescription("请求数"), metric.WithUnit("{request}")); err != nil {
+		return err
+	}
+	if httpErrors, err = m.Int64Counter("http.server.errors",
+		metric.WithDescription("服务端错误数（状态码>=500）"), metric.WithUnit("{request}")); err != nil {
+		return err
+	}
+	if httpDuration, err = m.Float64Histogram("http.server.duration",
+		metric.WithDescription("请求耗时"), metric.WithUnit("ms")); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RecordRequest 记录一次请求的 RED 指标
+func RecordRequest(ctx context.Context, route, method string, status int, durationMs float64) {
+	if httpRequests == nil {
+		return
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("http.route", route),
+		attribute.String("http.request.method", method),
+		attribute.Int("http.response.status_code", status),
+	)
+	httpRequests.Add(ctx, 1, attrs)
+	httpDuration.Record(ctx, durationMs, attrs)
+	if status >= 500 {
+		httpErrors.Add(ctx, 1, attrs)
+	}
+}
+
+// RegisterDBStats 以异步指标的形式上报连接池状态
+func RegisterDBStats(db *sql.DB) error {
+	m := meter()
+	open, err := m.Int64ObservableGauge("db.client.connections.open", metric.WithDescription("已建立的连接数"))
+	if err != nil {
+		return err
+	}
+	inUse, err := m.Int64ObservableGauge("db.client.connections.in_use", metric.WithDescription("使用中的连接数"))
+	if err != nil {
+		return err
+	}
+	idle, err := m.Int64ObservableGauge("db.client.connections.idle", metric.WithDescription("空闲连接数"))
+	if err != nil {
+		return err
+	}
+	waitCount, err := m.Int64ObservableCounter("db.client.connections.wait_count", metric.WithDescription("等待连接的总次数"))
+	if err != nil {
+		return err
+	}
+	waitDuration, err := m.Float64ObservableCounter("db.client.connections.wait_duration",
+		metric.WithDescription("等待连接的总耗时"), metric.WithUnit("ms"))
+	if err != nil {
+		return err
+	}
+	_, err = m.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		stats := db.Stats()
+		o.ObserveInt64(open, int64(stats.OpenConnections))
+		o.ObserveInt64(inUse, int64(stats.InUse))
+		o.ObserveInt64(idle, int64(stats.Idle))
+		o.ObserveInt64(waitCount, stats.WaitCount)
+		o.ObserveFloat64(waitDuration, float64(stats.WaitDuration.Microseconds())/1e3)
+		return nil
+	}, open, inUse, idle, waitCount, waitDuration)
+	return err
+}
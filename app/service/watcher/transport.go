@@ -0,0 +1,106 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"gpm/app/service/log"
+	"gpm/conf"
+	"gpm/global"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultChannel 默认通知频道
+const DefaultChannel = "gpm_casbin"
+
+// maxPayload pg_notify 负载上限为 8000 字节，超出时改发全量重载消息
+const maxPayload = 7900
+
+// Transport 策略变更消息的传输方式
+type Transport interface {
+	// Publish 广播消息，实例自身也可能收到
+	Publish(ctx context.Context, payload []byte) error
+	// Subscribe 持续接收消息直到 ctx 结束，payload 为 nil 表示连接中断后恢复，需要对齐版本
+	Subscribe(ctx context.Context, handler func(payload []byte))
+}
+
+// NewTransport 按配置创建传输方式，未配置时返回 nil
+func NewTransport(wc conf.Watcher) (Transport, error) {
+	switch wc.Type {
+	case "":
+		return nil, nil
+	case "poll":
+		return PollTransport{}, nil
+	case "postgres":
+		channel := wc.Channel
+		if channel == "" {
+			channel = DefaultChannel
+		}
+		if len(global.Config.DB) == 0 {
+			return nil, fmt.Errorf("数据库未配置")
+		}
+		return &PostgresTransport{Channel: channel, Dsn: global.Config.DB[0].PgsqlDsn()}, nil
+	default:
+		return nil, fmt.Errorf("未知的策略同步方式: %s", wc.Type)
+	}
+}
+
+// PollTransport 不发送消息，仅依赖轮询版本号
+type PollTransport struct{}
+
+func (PollTransport) Publish(ctx context.Context, payload []byte) error {
+	return nil
+}
+
+func (PollTransport) Subscribe(ctx context.Context, handler func(payload []byte)) {
+}
+
+// PostgresTransport 基于 PostgreSQL LISTEN/NOTIFY
+type PostgresTransport struct {
+	Channel string
+	Dsn     string
+}
+
+func (t *PostgresTransport) Publish(ctx context.Context, payload []byte) error {
+	return global.DB.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", t.Channel, string(payload)).Error
+}
+
+// Subscribe 使用独立连接监听频道，断开后指数退避重连
+func (t *PostgresTransport) Subscribe(ctx context.Context, handler func(payload []byte)) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		err := t.listen(ctx, handler, func() { backoff = time.Second })
+		if ctx.Err() != nil {
+			return
+		}
+		log.Ctx(ctx).Warnf("策略变更监听中断，%s 后重连: %s", backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, time.Minute)
+	}
+}
+
+func (t *PostgresTransport) listen(ctx context.Context, handler func(payload []byte), connected func()) error {
+	conn, err := pgx.Connect(ctx, t.Dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+	if _, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{t.Channel}.Sanitize()); err != nil {
+		return err
+	}
+	connected()
+	// 监听建立前的变更可能丢失，先对齐一次版本
+	handler(nil)
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		handler([]byte(n.Payload))
+	}
+}
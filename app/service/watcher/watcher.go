@@ -0,0 +1,283 @@
+// package watcher: 多实例间同步 Casbin 策略变更
+// 每次变更将 casbin_version 加一并广播消息，其他实例收到连续版本的消息时增量应用，
+// 版本不连续或无法增量应用时全量重新加载，轮询版本号用于兜底丢失的消息
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"gpm/app/model"
+	"gpm/app/service/log"
+	"gpm/global"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	casbinmodel "github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// 消息类型
+const (
+	MethodReload = "reload"
+	MethodAdd    = "add"
+	MethodRemove = "remove"
+)
+
+// Message 策略变更消息
+type Message struct {
+	Instance string     `json:"instance"`
+	Version  int64      `json:"version"`
+	Method   string     `json:"method"`
+	Sec      string     `json:"sec,omitempty"`
+	Ptype    string     `json:"ptype,omitempty"`
+	Rules    [][]string `json:"rules,omitempty"`
+}
+
+// instance 当前实例标识，用于忽略自己发出的消息
+var instance = uuid.NewString()
+
+// active 当前生效的 Watcher，供直接修改 casbin_rule 的代码通知其他实例
+var active *Watcher
+
+// Watcher 实现 persist.WatcherEx，收到消息后直接操作 enforcer，不使用 SetUpdateCallback 注册的回调
+// 增量应用时持有 enforcer 的写锁，避免与鉴权并发读写策略
+type Watcher struct {
+	enforcer  *casbin.SyncedEnforcer
+	transport Transport
+
+	// mu 串行化消息处理与重新加载；publish 在 enforcer 持锁期间回调，不能获取 mu，只通过 version 原子前移
+	mu      sync.Mutex
+	version atomic.Int64 // 本实例已同步到的版本
+}
+
+var _ persist.WatcherEx = (*Watcher)(nil)
+
+// New 创建 Watcher，需随后调用 enforcer.SetWatcher 与 Start
+func New(e *casbin.SyncedEnforcer, t Transport) *Watcher {
+	return &Watcher{enforcer: e, transport: t}
+}
+
+// Start 记录当前版本并开始接收消息与轮询，interval 为 0 时不轮询
+func (w *Watcher) Start(ctx context.Context, interval time.Duration) error {
+	version, err := currentVersion(ctx)
+	if err != nil {
+		return err
+	}
+	w.version.Store(version)
+	active = w
+	log.Go(ctx, func(ctx context.Context) {
+		w.transport.Subscribe(ctx, func(payload []byte) { w.receive(ctx, payload) })
+	})
+	if interval > 0 {
+		log.Go(ctx, func(ctx context.Context) { w.poll(ctx, interval) })
+	}
+	return nil
+}
+
+func (w *Watcher) SetUpdateCallback(func(string)) error {
+	return nil
+}
+
+func (w *Watcher) Update() error {
+	return w.publish(Message{Method: MethodReload})
+}
+
+func (w *Watcher) Close() {
+	if active == w {
+		active = nil
+	}
+}
+
+func (w *Watcher) UpdateForAddPolicy(sec, ptype string, params ...string) error {
+	return w.publish(Message{Method: MethodAdd, Sec: sec, Ptype: ptype, Rules: [][]string{params}})
+}
+
+func (w *Watcher) UpdateForRemovePolicy(sec, ptype string, params ...string) error {
+	return w.publish(Message{Method: MethodRemove, Sec: sec, Ptype: ptype, Rules: [][]string{params}})
+}
+
+func (w *Watcher) UpdateForRemoveFilteredPolicy(sec, ptype string, fieldIndex int, fieldValues ...string) error {
+	return w.Update()
+}
+
+func (w *Watcher) UpdateForSavePolicy(model casbinmodel.Model) error {
+	return w.Update()
+}
+
+func (w *Watcher) UpdateForAddPolicies(sec string, ptype string, rules ...[]string) error {
+	return w.publish(Message{Method: MethodAdd, Sec: sec, Ptype: ptype, Rules: rules})
+}
+
+func (w *Watcher) UpdateForRemovePolicies(sec string, ptype string, rules ...[]string) error {
+	return w.publish(Message{Method: MethodRemove, Sec: sec, Ptype: ptype, Rules: rules})
+}
+
+// publish 递增版本号后广播，本实例版本连续时直接前移
+func (w *Watcher) publish(msg Message) error {
+	ctx := context.Background()
+	version, err := send(ctx, w.transport, msg)
+	if err != nil {
+		return err
+	}
+	w.version.CompareAndSwap(version-1, version)
+	return nil
+}
+
+// receive 处理其他实例的消息，payload 为 nil 时仅对齐版本
+func (w *Watcher) receive(ctx context.Context, payload []byte) {
+	if payload == nil {
+		w.sync(ctx)
+		return
+	}
+	var msg Message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		log.Ctx(ctx).Warnf("无法解析策略变更消息: %s", err)
+		return
+	}
+	if msg.Instance == instance {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	current := w.version.Load()
+	if msg.Version <= current {
+		return
+	}
+	if msg.Version == current+1 && w.apply(ctx, msg) {
+		w.version.CompareAndSwap(current, msg.Version)
+		return
+	}
+	w.reload(ctx)
+}
+
+// apply 增量应用消息，无法增量处理时返回 false
+func (w *Watcher) apply(ctx context.Context, msg Message) bool {
+	lock := w.enforcer.GetLock()
+	lock.Lock()
+	defer lock.Unlock()
+	var err error
+	switch msg.Method {
+	case MethodAdd:
+		var affected [][]string
+		affected, err = w.enforcer.GetModel().AddPoliciesWithAffected(msg.Sec, msg.Ptype, msg.Rules)
+		if err == nil && msg.Sec == "g" && len(affected) > 0 {
+			err = w.enforcer.BuildIncrementalRoleLinks(casbinmodel.PolicyAdd, msg.Ptype, affected)
+		}
+	case MethodRemove:
+		var affected [][]string
+		affected, err = w.enforcer.GetModel().RemovePoliciesWithAffected(msg.Sec, msg.Ptype, msg.Rules)
+		if err == nil && msg.Sec == "g" && len(affected) > 0 {
+			err = w.enforcer.BuildIncrementalRoleLinks(casbinmodel.PolicyRemove, msg.Ptype, affected)
+		}
+	default:
+		return false
+	}
+	if err != nil {
+		log.Ctx(ctx).Warnf("增量同步策略失败，改为全量加载: %s", err)
+		return false
+	}
+	return true
+}
+
+// sync 数据库版本领先时全量加载
+func (w *Watcher) sync(ctx context.Context) {
+	version, err := currentVersion(ctx)
+	if err != nil {
+		log.Ctx(ctx).Warnf("读取策略版本失败: %s", err)
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if version > w.version.Load() {
+		w.reload(ctx)
+	}
+}
+
+// reload 先读版本再加载策略，加载期间的新变更会再次触发同步，需持有 mu
+func (w *Watcher) reload(ctx context.Context) {
+	version, err := currentVersion(ctx)
+	if err != nil {
+		log.Ctx(ctx).Warnf("读取策略版本失败: %s", err)
+		return
+	}
+	if err = w.enforcer.LoadPolicy(); err != nil {
+		log.Ctx(ctx).Errorf("重新加载策略失败: %s", err)
+		return
+	}
+	w.version.Store(version)
+	log.Ctx(ctx).Infof("策略已重新加载，版本 %d", version)
+}
+
+func (w *Watcher) poll(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.sync(ctx)
+		}
+	}
+}
+
+// Notify 通知其他实例全量重新加载，用于绕过 Enforcer 直接修改 casbin_rule 的场景（如命令行导入）
+func Notify(ctx context.Context) error {
+	if active != nil {
+		return active.Update()
+	}
+	t, err := NewTransport(global.Config.Watcher)
+	if err != nil || t == nil {
+		return err
+	}
+	_, err = send(ctx, t, Message{Method: MethodReload})
+	return err
+}
+
+// send 递增版本号并广播，消息过大时改为全量重载
+func send(ctx context.Context, t Transport, msg Message) (int64, error) {
+	version, err := bumpVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+	msg.Instance = instance
+	msg.Version = version
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return 0, err
+	}
+	if len(payload) > maxPayload {
+		payload, err = json.Marshal(Message{Instance: instance, Version: version, Method: MethodReload})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return version, t.Publish(ctx, payload)
+}
+
+func bumpVersion(ctx context.Context) (int64, error) {
+	row := model.CasbinVersion{ID: 1, Version: 1}
+	err := global.DB.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.Assignments(map[string]any{"version": clause.Expr{SQL: "casbin_version.version + 1"}, "update_at": int(time.Now().Unix())}),
+		},
+		clause.Returning{Columns: []clause.Column{{Name: "version"}}},
+	).Create(&row).Error
+	return row.Version, err
+}
+
+func currentVersion(ctx context.Context) (int64, error) {
+	var rows []model.CasbinVersion
+	if err := global.DB.WithContext(ctx).Where("id = ?", 1).Find(&rows).Error; err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0].Version, nil
+}
@@ -0,0 +1,94 @@
+// package client: gpm 客户端 SDK，生成与服务端一致的请求签名
+package client
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"gpm/common/sign"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Signer 请求签名器，配置 KeyID/Secret 使用租户密钥，否则使用旧版 Prefix/Suffix
+type Signer struct {
+	Tenant string
+	KeyID  string
+	Secret string
+	Prefix string
+	Suffix string
+	// Now 可替换的时间来源，为空使用 time.Now
+	Now func() time.Time
+}
+
+// Headers 签名请求头
+type Headers map[string]string
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Sign 为请求生成签名请求头，uri 为路径加查询参数，如 /gpm/api?page=1
+func (s *Signer) Sign(uri string, body []byte) (Headers, error) {
+	if s.Tenant == "" {
+		return nil, errors.New("tenant 不能为空")
+	}
+	canonicalBody, err := sign.CanonicalBody(body)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	timestamp := strconv.FormatInt(now().Unix(), 10)
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, err
+	}
+	message := sign.Message(s.Tenant, uri, canonicalBody, timestamp, nonce)
+	headers := Headers{
+		sign.HeaderTenant:    s.Tenant,
+		sign.HeaderTimestamp: timestamp,
+		sign.HeaderNonce:     nonce,
+	}
+	if s.KeyID != "" {
+		headers[sign.HeaderKeyID] = s.KeyID
+		headers[sign.HeaderSignature] = sign.HMAC(s.Secret, message)
+	} else {
+		headers[sign.HeaderSignature] = sign.Legacy(s.Prefix, s.Suffix, message)
+	}
+	return headers, nil
+}
+
+// SignRequest 读取请求体计算签名并写入请求头，请求体会被恢复供后续发送
+func (s *Signer) SignRequest(req *http.Request) error {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	headers, err := s.Sign(req.URL.RequestURI(), body)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package client
+
+import (
+	"net/http"
+)
+
+// Transport 自动为每个请求签名的 http.RoundTripper
+//
+//	httpClient := &http.Client{Transport: &client.Transport{Signer: signer}}
+type Transport struct {
+	Signer *Signer
+	// Base 实际发送请求的 RoundTripper，为空使用 http.DefaultTransport
+	Base http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTripper 不应修改原请求
+	signed := req.Clone(req.Context())
+	if err := t.Signer.SignRequest(signed); err != nil {
+		return nil, err
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(signed)
+}
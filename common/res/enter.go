@@ -13,6 +13,8 @@ const (
 	FailAuthCode    Code = 1002 // 权限不足
 	FailServiceCode Code = 1003 // 服务错误
 	FailTokenCode   Code = 1004 // token不合法
+	FailReplayCode  Code = 1005 // 请求重放
+	FailExpireCode  Code = 1006 // 请求时间超出允许范围
 )
 
 func (c Code) String() string {
@@ -27,6 +29,10 @@ func (c Code) String() string {
 		return "token不合法"
 	case FailServiceCode:
 		return "服务异常"
+	case FailReplayCode:
+		return "请求重复提交"
+	case FailExpireCode:
+		return "请求已过期"
 	default:
 		return "未知错误"
 	}
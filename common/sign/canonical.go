@@ -0,0 +1,62 @@
+// package sign: 请求签名规范化，服务端 ArgsCheckMiddleware 与客户端 SDK 共用
+package sign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// 签名相关请求头
+const (
+	HeaderTenant    = "tenant"
+	HeaderTimestamp = "timestamp"
+	HeaderNonce     = "nonce"
+	HeaderSignature = "signature"
+	HeaderKeyID     = "key-id"
+)
+
+// CanonicalBody 使用“解析再封装”方法规范化请求体
+// 解析为通用结构后重新序列化，得到紧凑、无多余空格、键已排序的 JSON
+// 注意：数字按 float64 处理，HTML 字符（< > &）会被转义为 \u003c 等形式
+func CanonicalBody(body []byte) ([]byte, error) {
+	if len(body) == 0 {
+		return nil, nil
+	}
+	var jsonData interface{}
+	if err := json.Unmarshal(body, &jsonData); err != nil {
+		return nil, fmt.Errorf("请求体格式不合法: %w", err)
+	}
+	return json.Marshal(jsonData)
+}
+
+// Message 拼接签名原文：tenant + uri + 规范化请求体 + timestamp + nonce
+// uri 为请求行中的原始路径（含查询参数），如 /gpm/api?page=1
+func Message(tenant, uri string, canonicalBody []byte, timestamp, nonce string) []byte {
+	return []byte(tenant + uri + string(canonicalBody) + timestamp + nonce)
+}
+
+// HMAC 租户密钥签名：HMAC-SHA256 后 base64 编码
+func HMAC(secret string, message []byte) string {
+	return base64.StdEncoding.EncodeToString(HMACBytes(secret, message))
+}
+
+// HMACBytes 返回 HMAC-SHA256 原始字节
+func HMACBytes(secret string, message []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(message)
+	return mac.Sum(nil)
+}
+
+// Legacy 旧版全局前后缀签名：SHA-256(prefix + 原文 + suffix) 后 base64 编码
+func Legacy(prefix, suffix string, message []byte) string {
+	return base64.StdEncoding.EncodeToString(LegacyBytes(prefix, suffix, message))
+}
+
+// LegacyBytes 返回旧版签名原始字节
+func LegacyBytes(prefix, suffix string, message []byte) []byte {
+	sum := sha256.Sum256([]byte(prefix + string(message) + suffix))
+	return sum[:]
+}
@@ -3,4 +3,14 @@ package conf
 type ArgsCheck struct {
 	Prefix string
 	Suffix string
+	Window int             `yaml:"window"` //允许的时间戳偏差（秒），为空为 300
+	Legacy bool            `yaml:"legacy"` //未携带 key-id 时是否允许使用全局前后缀的旧版签名
+	Mode   string          `yaml:"mode"`   //默认校验模式 required optional off，为空为 required
+	Rules  []ArgsCheckRule `yaml:"rules"`  //按路径覆盖校验模式，按顺序匹配第一条
+}
+
+// ArgsCheckRule 路径校验规则
+type ArgsCheckRule struct {
+	Path string `yaml:"path"` //路径模式，支持 path.Match 通配符，以 /** 结尾表示匹配整个子路径
+	Mode string `yaml:"mode"` //required optional off
 }
@@ -0,0 +1,6 @@
+package conf
+
+type Audit struct {
+	CheckpointKey      string `yaml:"checkpointKey"`      //检查点签名私钥种子（base64，32字节 ed25519 seed）
+	CheckpointInterval int    `yaml:"checkpointInterval"` //检查点生成间隔（秒，0=不生成）
+}
@@ -1,9 +1,18 @@
 package conf
 
 type Config struct {
-	System    System `yaml:"system"`
-	Log       Log    `yaml:"log"`
-	DB        []DB   `yaml:"db"` //数据库连接列表
-	Jwt       Jwt    `yaml:"jwt"`
-	ArgsCheck ArgsCheck
+	System     System `yaml:"system"`
+	Log        Log    `yaml:"log"`
+	DB         []DB   `yaml:"db"` //数据库连接列表
+	Jwt        Jwt    `yaml:"jwt"`
+	ArgsCheck  ArgsCheck
+	Audit      Audit      `yaml:"audit"`      //操作日志审计
+	Telemetry  Telemetry  `yaml:"telemetry"`  //链路追踪与指标
+	Metrics    Metrics    `yaml:"metrics"`    //Prometheus 指标
+	Oidc       Oidc       `yaml:"oidc"`       //外部身份提供方登录
+	OidcServer OidcServer `yaml:"oidcServer"` //作为 OIDC 身份提供方
+	Mail       Mail       `yaml:"mail"`       //邮件发送
+	Session    Session    `yaml:"session"`    //登录会话
+	Watcher    Watcher    `yaml:"watcher"`    //多实例间同步 Casbin 策略
+	Grant      Grant      `yaml:"grant"`      //临时授权
 }
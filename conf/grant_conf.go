@@ -0,0 +1,6 @@
+package conf
+
+type Grant struct {
+	MaxDuration   int `yaml:"maxDuration"`   //临时授权最长时长（秒，默认 86400）
+	SweepInterval int `yaml:"sweepInterval"` //到期临时授权回收间隔（秒，默认 60）
+}
@@ -1,9 +1,13 @@
 package conf
 
 type Jwt struct {
-	AccessExpire  int    `yaml:"accessExpire"`
-	RefreshExpire int    `yaml:"refreshExpire"`
-	AccessSecret  string `yaml:"accessSecret"`
-	RefreshSecret string `yaml:"refreshSecret"`
-	Issuer        string `yaml:"issuer"`
+	AccessExpire      int    `yaml:"accessExpire"`
+	RefreshExpire     int    `yaml:"refreshExpire"`
+	AccessSecret      string `yaml:"accessSecret"`
+	RefreshSecret     string `yaml:"refreshSecret"`
+	Issuer            string `yaml:"issuer"`
+	Alg               string `yaml:"alg"`               //令牌签名算法 HS256（默认，使用上面两个密钥）、RS256、ES256、EdDSA
+	RotateInterval    int    `yaml:"rotateInterval"`    //非对称签名密钥轮换间隔（秒，0=不自动轮换）
+	RotateGrace       int    `yaml:"rotateGrace"`       //轮换后旧公钥保留时间（秒），应不小于刷新令牌有效期
+	ImpersonateExpire int    `yaml:"impersonateExpire"` //模拟登录令牌有效期（秒，默认 900）
 }
@@ -1,7 +1,23 @@
 package conf
 
 type Log struct {
-	Debug bool   `yaml:"debug"`
-	App   string `yaml:"app"`
-	Dir   string `yaml:"dir"`
+	Debug bool      `yaml:"debug"`
+	App   string    `yaml:"app"`
+	Dir   string    `yaml:"dir"`
+	Sinks []LogSink `yaml:"sinks"` //日志输出端，为空时使用按小时切分的文件和标准输出
+}
+
+// LogSink 单个日志输出端配置
+type LogSink struct {
+	Type     string `yaml:"type"`     //file stdout syslog udp
+	Level    string `yaml:"level"`    //最低输出级别 trace debug info warn error，为空为 debug
+	Format   string `yaml:"format"`   //json text，为空为 json
+	Rotate   string `yaml:"rotate"`   //file: 按时间切分 hour day，为空为 hour
+	MaxSize  int    `yaml:"maxSize"`  //file: 单文件最大大小（MB，0=不限制）
+	MaxAge   int    `yaml:"maxAge"`   //file: 保留天数（0=不清理）
+	MaxCount int    `yaml:"maxCount"` //file: 保留的历史文件数（0=不限制）
+	Compress bool   `yaml:"compress"` //file: 是否 gzip 压缩已关闭的文件
+	Network  string `yaml:"network"`  //syslog udp: udp tcp，为空为 udp
+	Addr     string `yaml:"addr"`     //syslog udp: 目标地址 host:port
+	Tag      string `yaml:"tag"`      //syslog: 标识，为空使用 app
 }
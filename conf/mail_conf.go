@@ -0,0 +1,15 @@
+package conf
+
+type Mail struct {
+	Type         string `yaml:"type"`         //发送方式 smtp、file、log（默认 log）
+	Host         string `yaml:"host"`         //SMTP 服务器
+	Port         int    `yaml:"port"`         //SMTP 端口，465 使用隐式 TLS，其余端口在服务器支持时使用 STARTTLS
+	Username     string `yaml:"username"`     //SMTP 用户名
+	Password     string `yaml:"password"`     //SMTP 密码
+	From         string `yaml:"from"`         //发件人地址
+	Dir          string `yaml:"dir"`          //file 方式的输出目录
+	VerifyURL    string `yaml:"verifyUrl"`    //邮箱验证页面地址，邮件中附带 ?token=
+	ResetURL     string `yaml:"resetUrl"`     //重置密码页面地址，邮件中附带 ?token=
+	VerifyExpire int    `yaml:"verifyExpire"` //邮箱验证链接有效期（秒，默认 86400）
+	ResetExpire  int    `yaml:"resetExpire"`  //重置密码链接有效期（秒，默认 1800）
+}
@@ -0,0 +1,6 @@
+package conf
+
+type Metrics struct {
+	Enable bool   `yaml:"enable"` //是否暴露 Prometheus 指标
+	Path   string `yaml:"path"`   //指标路径，为空为 /metrics
+}
@@ -0,0 +1,19 @@
+package conf
+
+type Oidc struct {
+	StateExpire int            `yaml:"stateExpire"` //登录状态有效期（秒，默认 600）
+	Providers   []OidcProvider `yaml:"providers"`   //外部身份提供方列表
+}
+
+type OidcProvider struct {
+	Name          string   `yaml:"name"`          //唯一标识，对应路由 /gpm/oidc/{name}/login
+	Issuer        string   `yaml:"issuer"`        //签发方地址，用于获取 /.well-known/openid-configuration
+	ClientID      string   `yaml:"clientId"`      //客户端 ID
+	ClientSecret  string   `yaml:"clientSecret"`  //客户端密钥，公开客户端可为空
+	RedirectURL   string   `yaml:"redirectUrl"`   //回调地址，如 https://gpm.example.com/gpm/oidc/{name}/callback
+	Scopes        []string `yaml:"scopes"`        //额外 scope，openid 会自动添加
+	LinkByEmail   bool     `yaml:"linkByEmail"`   //是否按已验证的邮箱关联已有用户
+	AutoProvision bool     `yaml:"autoProvision"` //是否为未关联的身份自动创建用户
+	Tenant        string   `yaml:"tenant"`        //自动创建的用户所属租户
+	DefaultRole   string   `yaml:"defaultRole"`   //自动创建的用户在租户下的默认角色 ID
+}
@@ -0,0 +1,8 @@
+package conf
+
+type OidcServer struct {
+	Issuer      string `yaml:"issuer"`      //签发方地址，如 https://gpm.example.com/gpm，为空时按请求地址推断
+	LoginURL    string `yaml:"loginUrl"`    //未登录时跳转的登录页，会附带 redirect 参数
+	CodeExpire  int    `yaml:"codeExpire"`  //授权码有效期（秒，默认 60）
+	TokenExpire int    `yaml:"tokenExpire"` //访问令牌与 id_token 有效期（秒，默认 3600）
+}
@@ -0,0 +1,14 @@
+package conf
+
+type Session struct {
+	MaxPerUser   int            `yaml:"maxPerUser"`   //每个用户的并发会话上限，0 表示不限制，超出时踢出最早的会话
+	TenantLimits map[string]int `yaml:"tenantLimits"` //按租户覆盖并发会话上限，键为租户标识
+}
+
+// Limit 返回租户下的并发会话上限
+func (s Session) Limit(tenant string) int {
+	if n, ok := s.TenantLimits[tenant]; ok {
+		return n
+	}
+	return s.MaxPerUser
+}
@@ -3,9 +3,11 @@ package conf
 import "fmt"
 
 type System struct {
-	IP   string `yaml:"ip"`
-	Port int    `yaml:"port"`
-	Env  string `yaml:"env"`
+	IP             string   `yaml:"ip"`
+	Port           int      `yaml:"port"`
+	Env            string   `yaml:"env"`
+	TrustedProxies []string `yaml:"trustedProxies"` //可信反向代理 IP 或 CIDR，为空时不信任 X-Forwarded-For，客户端 IP 取连接地址
+	PlatformTenant string   `yaml:"platformTenant"` //平台租户，账号状态对全部租户生效，只能在该租户下修改；为空时不允许通过接口修改
 }
 
 func (s System) Addr() string {
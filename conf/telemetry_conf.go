@@ -0,0 +1,11 @@
+package conf
+
+type Telemetry struct {
+	Enable         bool    `yaml:"enable"`         //是否启用 OpenTelemetry
+	ServiceName    string  `yaml:"serviceName"`    //服务名，为空使用 log.app
+	Exporter       string  `yaml:"exporter"`       //otlp stdout
+	Endpoint       string  `yaml:"endpoint"`       //otlp: 采集端地址 host:port（http 协议）
+	Insecure       bool    `yaml:"insecure"`       //otlp: 是否使用明文 http
+	SampleRatio    float64 `yaml:"sampleRatio"`    //采样率 0~1，0 按 1 处理
+	MetricInterval int     `yaml:"metricInterval"` //指标上报间隔（秒），为空为 60
+}
@@ -0,0 +1,7 @@
+package conf
+
+type Watcher struct {
+	Type         string `yaml:"type"`         //策略变更同步方式 postgres（LISTEN/NOTIFY）、poll（轮询版本号），为空表示单实例不同步
+	Channel      string `yaml:"channel"`      //postgres 通知频道（默认 gpm_casbin）
+	PollInterval int    `yaml:"pollInterval"` //轮询版本号间隔（秒，poll 默认 5，postgres 作为兜底默认 60）
+}
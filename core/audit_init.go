@@ -0,0 +1,26 @@
+package core
+
+import (
+	"context"
+	"gpm/app/service/audit"
+	"gpm/app/service/log"
+	"gpm/global"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func InitAudit() {
+	interval := global.Config.Audit.CheckpointInterval
+	if interval <= 0 {
+		return
+	}
+	if _, err := audit.PublicKey(); err != nil {
+		logrus.Warnf("操作日志检查点未启用: %s", err)
+		return
+	}
+	log.Go(context.Background(), func(ctx context.Context) {
+		audit.RunCheckpointTicker(ctx, time.Duration(interval)*time.Second)
+	})
+	logrus.Infof("操作日志检查点已启用，间隔%d秒", interval)
+}
@@ -4,18 +4,25 @@ import (
 	"github.com/casbin/casbin/v2"
 	gormadapter "github.com/casbin/gorm-adapter/v3"
 	"github.com/sirupsen/logrus"
+	"gpm/app/service/casbin_service"
 	"gpm/global"
 )
 
-func InitCasbin() *casbin.Enforcer {
+// InitCasbin 策略会被 Watcher 在后台增量修改，使用 SyncedEnforcer 保证与鉴权并发安全
+func InitCasbin() *casbin.SyncedEnforcer {
 	a, err := gormadapter.NewAdapterByDBUseTableName(global.DB, "", "casbin_rule")
 	if err != nil {
 		logrus.Fatal(err)
 	}
-	e, err := casbin.NewEnforcer("conf/rbac_with_domains_model.conf", a)
+	if err = migratePolicy(); err != nil {
+		logrus.Fatal(err)
+	}
+	e, err := casbin.NewSyncedEnforcer("conf/rbac_with_domains_model.conf", a)
 	if err != nil {
 		logrus.Fatal(err)
 	}
+	e.AddFunction("objMatch", casbin_service.ObjMatchFunc)
+	e.AddFunction("actMatch", casbin_service.ActMatchFunc)
 
 	// Or you can use an existing DB "abc" like this:
 	// The adapter will use the table named "casbin_rule".
@@ -29,3 +36,19 @@ func InitCasbin() *casbin.Enforcer {
 	}
 	return e
 }
+
+// migratePolicy 策略增加条件列 cond、效果列 eft 后，旧策略补为无条件允许
+// gorm-adapter 加载时会去掉末尾的空值，不补齐会导致策略长度与模型不一致
+func migratePolicy() error {
+	err := global.DB.Exec("UPDATE casbin_rule SET v4 = ? WHERE ptype = 'p' AND (v4 = '' OR v4 IS NULL)", casbin_service.DefaultCondition).Error
+	if err != nil {
+		return err
+	}
+	err = global.DB.Exec("UPDATE casbin_rule SET v5 = ? WHERE ptype = 'p' AND (v5 = '' OR v5 IS NULL)", casbin_service.EffectAllow).Error
+	if err != nil {
+		return err
+	}
+	// 接口对象统一为 api:<路由路径>，CasbinMiddleware 按路径判定，按接口ID写入的策略永远不会命中
+	return global.DB.Exec(`UPDATE casbin_rule SET v2 = 'api:' || api.path FROM api
+		WHERE casbin_rule.ptype = 'p' AND casbin_rule.v2 = 'api:' || api.id::text AND api.path LIKE '/%'`).Error
+}
@@ -0,0 +1,23 @@
+package core
+
+import (
+	"context"
+	"gpm/app/service/grant"
+	"gpm/app/service/log"
+	"gpm/global"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// InitGrant 启动到期临时授权的回收任务，多实例同时运行时状态更新保证只回收一次
+func InitGrant() {
+	interval := grant.DefaultSweepInterval
+	if n := global.Config.Grant.SweepInterval; n > 0 {
+		interval = time.Duration(n) * time.Second
+	}
+	log.Go(context.Background(), func(ctx context.Context) {
+		grant.RunSweeper(ctx, interval)
+	})
+	logrus.Infof("临时授权回收任务已启动，间隔%s", interval)
+}
@@ -0,0 +1,34 @@
+package core
+
+import (
+	"context"
+	"gpm/app/service/keyring"
+	"gpm/app/service/log"
+	"gpm/global"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// InitKeyring 加载签名密钥环；配置算法与当前密钥不一致时立即轮换，并按间隔自动轮换
+func InitKeyring() {
+	ctx := context.Background()
+	if err := keyring.Load(ctx); err != nil {
+		logrus.Fatalf("签名密钥加载失败: %s", err)
+	}
+	jc := global.Config.Jwt
+	grace := time.Duration(jc.RotateGrace) * time.Second
+	if key, err := keyring.Current(ctx); err == nil && key.Alg != keyring.Alg() {
+		if _, err = keyring.Rotate(ctx, grace, 0); err != nil {
+			logrus.Fatalf("签名密钥轮换失败: %s", err)
+		}
+		logrus.Infof("签名算法变更为 %s，已轮换签名密钥", keyring.Alg())
+	}
+	if jc.RotateInterval <= 0 {
+		return
+	}
+	log.Go(ctx, func(ctx context.Context) {
+		keyring.RunRotateTicker(ctx, time.Duration(jc.RotateInterval)*time.Second, grace)
+	})
+	logrus.Infof("签名密钥自动轮换已启用，间隔%d秒", jc.RotateInterval)
+}
@@ -1,98 +1,159 @@
 package core
 
 import (
-	"context"
 	"fmt"
+	"gpm/app/service/log"
+	"gpm/conf"
 	"gpm/global"
+	"io"
 	"os"
-	"strconv"
-	"time"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 )
 
-func initFile(logPath, appName string) {
-	fileName := time.Now().Format("2006010215")
-	err := os.MkdirAll(fmt.Sprintf("%s", logPath), os.ModePerm)
-	if err != nil {
-		logrus.Error(err)
-		return
-	}
+// LogSink 日志输出端，接收原始日志条目和已格式化好的一行内容
+type LogSink interface {
+	Write(entry *logrus.Entry, line []byte) error
+	Close() error
+}
 
-	// 按小时创建日志文件
-	filename := fmt.Sprintf("%s/%s.%s.log", logPath, appName, fileName)
-	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
-	if err != nil {
-		logrus.Error(err)
-		return
+// LogSinkFactory 根据配置创建日志输出端
+type LogSinkFactory func(c conf.LogSink) (LogSink, error)
+
+var (
+	sinkFactories = map[string]LogSinkFactory{}
+	activeSinks   []LogSink
+	sinkMu        sync.Mutex
+)
+
+// RegisterLogSink 注册日志输出端类型，需在 InitLogrus 之前调用
+func RegisterLogSink(name string, factory LogSinkFactory) {
+	sinkFactories[name] = factory
+}
+
+func init() {
+	RegisterLogSink("file", newFileSink)
+	RegisterLogSink("stdout", newStdoutSink)
+	RegisterLogSink("syslog", newSyslogSink)
+	RegisterLogSink("udp", newUDPSink)
+}
+
+// defaultSinks 未配置输出端时沿用按小时切分文件 + 控制台输出
+func defaultSinks() []conf.LogSink {
+	return []conf.LogSink{
+		{Type: "file", Rotate: "hour"},
+		{Type: "stdout"},
 	}
-	fileHook := FileDateHook{file, logPath, filename, appName}
-	logrus.AddHook(&fileHook)
 }
 
 func InitLogrus() {
 	//新建一个实例
 	logrus.SetReportCaller(false) //开启返回函数名和行号
 	logrus.SetFormatter(&logrus.JSONFormatter{})
-	logrus.SetLevel(logrus.DebugLevel)
-	initFile(global.Config.Log.Dir, global.Config.Log.App) //设置最低的Level
+	// 输出全部交给 sink，logrus 自身不再直接写 stderr
+	logrus.SetOutput(io.Discard)
+	logrus.AddHook(&fieldHook{})
+
+	sinks := global.Config.Log.Sinks
+	if len(sinks) == 0 {
+		sinks = defaultSinks()
+	}
+	minLevel := logrus.PanicLevel
+	for _, sc := range sinks {
+		hook, err := newSinkHook(sc)
+		if err != nil {
+			logrus.SetOutput(os.Stderr)
+			logrus.Fatalf("日志输出端 %s 初始化失败: %s", sc.Type, err)
+		}
+		if hook.level > minLevel {
+			minLevel = hook.level
+		}
+		logrus.AddHook(hook)
+	}
+	logrus.SetLevel(minLevel) //设置最低的Level
+	logrus.RegisterExitHandler(CloseLogSinks)
 	logrus.Infof("日志初始化成功")
 }
 
-type FileDateHook struct {
-	file     *os.File
-	logPath  string
-	fileName string // 小时，用于判断是否切换文件
-	appName  string
+// CloseLogSinks 刷新并关闭所有日志输出端
+func CloseLogSinks() {
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+	for _, s := range activeSinks {
+		_ = s.Close()
+	}
+	activeSinks = nil
 }
 
-func (hook *FileDateHook) Levels() []logrus.Level {
-	return logrus.AllLevels
+// sinkHook 将单个输出端适配为 logrus hook，负责级别过滤与格式化
+type sinkHook struct {
+	level     logrus.Level
+	formatter logrus.Formatter
+	sink      LogSink
 }
-func (hook *FileDateHook) Fire(entry *logrus.Entry) error {
-	entry.Data["app"] = global.Config.Log.App
-	if entry.Data["type"] == "" {
-		entry.Data["type"] = "system"
-	} else {
-		for k, v := range hook.getFiles(entry.Context) {
-			entry.Data[k] = v
-		}
+
+func newSinkHook(c conf.LogSink) (*sinkHook, error) {
+	factory, ok := sinkFactories[c.Type]
+	if !ok {
+		return nil, fmt.Errorf("未知的日志输出端类型: %s", c.Type)
 	}
-	currentDate := entry.Time.Format("2006010215")
-	line, _ := entry.String()
-	if hook.fileName == currentDate {
-		_, err := hook.file.Write([]byte(line))
+	level := logrus.DebugLevel
+	if c.Level != "" {
+		var err error
+		level, err = logrus.ParseLevel(c.Level)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		return nil
 	}
-	// 时间或小时变化，关闭旧文件，创建新文件
-	err := hook.file.Close()
+	var formatter logrus.Formatter
+	switch c.Format {
+	case "", "json":
+		formatter = &logrus.JSONFormatter{}
+	case "text":
+		formatter = &logrus.TextFormatter{DisableColors: true, FullTimestamp: true}
+	default:
+		return nil, fmt.Errorf("未知的日志格式: %s", c.Format)
+	}
+	sink, err := factory(c)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	// 创建新文件（按小时）
-	filename := fmt.Sprintf("%s/%s.%s.log", hook.logPath, hook.appName, currentDate)
-	hook.file, err = os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	sinkMu.Lock()
+	activeSinks = append(activeSinks, sink)
+	sinkMu.Unlock()
+	return &sinkHook{level: level, formatter: formatter, sink: sink}, nil
+}
+
+func (hook *sinkHook) Levels() []logrus.Level {
+	return logrus.AllLevels[:hook.level+1]
+}
+
+func (hook *sinkHook) Fire(entry *logrus.Entry) error {
+	line, err := hook.formatter.Format(entry)
 	if err != nil {
 		return err
 	}
-	_, err = hook.file.Write([]byte(line))
-	return err
+	return hook.sink.Write(entry, line)
 }
 
-func (hook *FileDateHook) getFiles(ctx context.Context) logrus.Fields {
-	fields := logrus.Fields{}
-	fields["logId"] = ""
-	fields["userId"] = "0"
-	if ctx != nil {
-		if logId, ok := ctx.Value("logId").(string); ok {
-			fields["logId"] = logId
-		}
-		if userId, ok := ctx.Value("userId").(uint); ok {
-			fields["userId"] = strconv.Itoa(int(userId))
+// fieldHook 为每条日志补充公共字段，需先于各输出端注册
+type fieldHook struct{}
+
+func (hook *fieldHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (hook *fieldHook) Fire(entry *logrus.Entry) error {
+	entry.Data["app"] = global.Config.Log.App
+	if t, ok := entry.Data["type"]; !ok || t == "" {
+		entry.Data["type"] = "system"
+	}
+	for k, v := range log.Fields(entry.Context) {
+		// 调用方显式传入的字段优先
+		if _, ok := entry.Data[k]; !ok {
+			entry.Data[k] = v
 		}
 	}
-	return fields
+	return nil
 }
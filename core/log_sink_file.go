@@ -0,0 +1,300 @@
+package core
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"gpm/conf"
+	"gpm/global"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	fileSinkBufferSize    = 64 * 1024
+	fileSinkFlushInterval = time.Second
+)
+
+var errSinkClosed = errors.New("日志输出端已关闭")
+
+// fileSink 按时间和大小切分的文件输出端，带写缓冲
+// 当前文件名为 {app}.{时间}.log，超出大小后重命名为 {app}.{时间}.{序号}.log
+type fileSink struct {
+	mu       sync.Mutex
+	dir      string
+	app      string
+	layout   string // 时间切分格式
+	maxSize  int64
+	maxAge   time.Duration
+	maxCount int
+	compress bool
+
+	file   *os.File
+	writer *bufio.Writer
+	period string // 当前文件对应的时间段
+	size   int64
+	closed bool
+	done   chan struct{}
+	jobs   chan string // 待压缩/清理的已关闭文件
+	wg     sync.WaitGroup
+}
+
+func newFileSink(c conf.LogSink) (LogSink, error) {
+	s := &fileSink{
+		dir:      global.Config.Log.Dir,
+		app:      global.Config.Log.App,
+		maxSize:  int64(c.MaxSize) * 1024 * 1024,
+		maxAge:   time.Duration(c.MaxAge) * 24 * time.Hour,
+		maxCount: c.MaxCount,
+		compress: c.Compress,
+		done:     make(chan struct{}),
+		jobs:     make(chan string, 16),
+	}
+	switch c.Rotate {
+	case "", "hour":
+		s.layout = "2006010215"
+	case "day":
+		s.layout = "20060102"
+	default:
+		return nil, fmt.Errorf("未知的日志切分方式: %s", c.Rotate)
+	}
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	if err := s.open(time.Now().Format(s.layout)); err != nil {
+		return nil, err
+	}
+	s.wg.Add(2)
+	go s.flushLoop()
+	go s.archiveLoop()
+	return s, nil
+}
+
+func (s *fileSink) activeName(period string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s.%s.log", s.app, period))
+}
+
+func (s *fileSink) open(period string) error {
+	file, err := os.OpenFile(s.activeName(period), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	s.file = file
+	s.writer = bufio.NewWriterSize(file, fileSinkBufferSize)
+	s.period = period
+	s.size = info.Size()
+	return nil
+}
+
+func (s *fileSink) Write(entry *logrus.Entry, line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errSinkClosed
+	}
+	if period := entry.Time.Format(s.layout); period != s.period {
+		// 时间段变化，关闭旧文件，创建新文件
+		if err := s.rotate(period, false); err != nil {
+			return err
+		}
+	} else if s.maxSize > 0 && s.size+int64(len(line)) > s.maxSize && s.size > 0 {
+		if err := s.rotate(period, true); err != nil {
+			return err
+		}
+	}
+	n, err := s.writer.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+// rotate 关闭当前文件并打开新文件，bySize 为真时先将当前文件重命名为带序号的文件
+func (s *fileSink) rotate(period string, bySize bool) error {
+	closedName, err := s.closeFile(bySize)
+	if err != nil {
+		return err
+	}
+	if err = s.open(period); err != nil {
+		return err
+	}
+	select {
+	case s.jobs <- closedName:
+	default:
+		// 队列已满时跳过，下次切分时的清理会覆盖
+	}
+	return nil
+}
+
+func (s *fileSink) closeFile(rename bool) (string, error) {
+	if err := s.writer.Flush(); err != nil {
+		return "", err
+	}
+	name := s.file.Name()
+	if err := s.file.Close(); err != nil {
+		return "", err
+	}
+	if !rename {
+		return name, nil
+	}
+	for i := 1; ; i++ {
+		target := filepath.Join(s.dir, fmt.Sprintf("%s.%s.%d.log", s.app, s.period, i))
+		if !fileExists(target) && !fileExists(target+".gz") {
+			return target, os.Rename(name, target)
+		}
+	}
+}
+
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+func (s *fileSink) flushLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(fileSinkFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			if !s.closed {
+				_ = s.writer.Flush()
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *fileSink) archiveLoop() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.done:
+			// 关闭前处理完剩余的文件
+			for {
+				select {
+				case name := <-s.jobs:
+					s.archive(name)
+				default:
+					return
+				}
+			}
+		case name := <-s.jobs:
+			s.archive(name)
+		}
+	}
+}
+
+func (s *fileSink) archive(name string) {
+	if s.compress {
+		if err := gzipFile(name); err != nil {
+			logrus.Warnf("日志文件压缩失败 %s: %s", name, err)
+		}
+	}
+	s.cleanup()
+}
+
+// gzipFile 将文件压缩为 name.gz 并删除原文件
+func gzipFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(name+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(dst)
+	if _, err = io.Copy(gw, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(name + ".gz")
+		return err
+	}
+	if err = gw.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	_ = src.Close()
+	return os.Remove(name)
+}
+
+// cleanup 按保留天数与保留数量删除历史文件，不处理当前正在写入的文件
+func (s *fileSink) cleanup() {
+	if s.maxAge <= 0 && s.maxCount <= 0 {
+		return
+	}
+	s.mu.Lock()
+	active := s.file.Name()
+	s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	type histFile struct {
+		path    string
+		modTime time.Time
+	}
+	var files []histFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, s.app+".") ||
+			!(strings.HasSuffix(name, ".log") || strings.HasSuffix(name, ".log.gz")) {
+			continue
+		}
+		path := filepath.Join(s.dir, name)
+		if path == active {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, histFile{path: path, modTime: info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+	for i, f := range files {
+		expired := s.maxAge > 0 && time.Since(f.modTime) > s.maxAge
+		overflow := s.maxCount > 0 && i >= s.maxCount
+		if expired || overflow {
+			_ = os.Remove(f.path)
+		}
+	}
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	err := s.writer.Flush()
+	if cerr := s.file.Close(); err == nil {
+		err = cerr
+	}
+	s.mu.Unlock()
+	close(s.done)
+	s.wg.Wait()
+	return err
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gpm/conf"
+	"gpm/global"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// stdoutSink 标准输出
+type stdoutSink struct {
+	mu sync.Mutex
+}
+
+func newStdoutSink(conf.LogSink) (LogSink, error) {
+	return &stdoutSink{}, nil
+}
+
+func (s *stdoutSink) Write(_ *logrus.Entry, line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := os.Stdout.Write(line)
+	return err
+}
+
+func (s *stdoutSink) Close() error {
+	return nil
+}
+
+// netSink 通过 udp/tcp 发送日志，syslog 为真时按 RFC 3164 添加报文头
+type netSink struct {
+	mu       sync.Mutex
+	network  string
+	addr     string
+	syslog   bool
+	tag      string
+	hostname string
+	conn     net.Conn
+	closed   bool
+}
+
+func newSyslogSink(c conf.LogSink) (LogSink, error) {
+	return newNetSink(c, true)
+}
+
+func newUDPSink(c conf.LogSink) (LogSink, error) {
+	return newNetSink(c, false)
+}
+
+func newNetSink(c conf.LogSink, syslog bool) (LogSink, error) {
+	if c.Addr == "" {
+		return nil, errors.New("未配置日志接收地址")
+	}
+	s := &netSink{
+		network: c.Network,
+		addr:    c.Addr,
+		syslog:  syslog,
+		tag:     c.Tag,
+	}
+	if s.network == "" {
+		s.network = "udp"
+	}
+	if s.tag == "" {
+		s.tag = global.Config.Log.App
+	}
+	s.hostname, _ = os.Hostname()
+	if err := s.dial(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *netSink) dial() error {
+	conn, err := net.DialTimeout(s.network, s.addr, 3*time.Second)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+// syslogSeverity logrus 级别到 syslog 严重级别的映射
+var syslogSeverity = map[logrus.Level]int{
+	logrus.PanicLevel: 0,
+	logrus.FatalLevel: 2,
+	logrus.ErrorLevel: 3,
+	logrus.WarnLevel:  4,
+	logrus.InfoLevel:  6,
+	logrus.DebugLevel: 7,
+	logrus.TraceLevel: 7,
+}
+
+func (s *netSink) Write(entry *logrus.Entry, line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errSinkClosed
+	}
+	msg := line
+	if s.syslog {
+		const facilityLocal0 = 16
+		pri := facilityLocal0*8 + syslogSeverity[entry.Level]
+		header := fmt.Sprintf("<%d>%s %s %s[%d]: ", pri, entry.Time.Format(time.Stamp), s.hostname, s.tag, os.Getpid())
+		msg = append([]byte(header), bytes.TrimRight(line, "\n")...)
+		if s.network != "udp" {
+			msg = append(msg, '\n')
+		}
+	}
+	if s.conn == nil {
+		if err := s.dial(); err != nil {
+			return err
+		}
+	}
+	if _, err := s.conn.Write(msg); err != nil {
+		// 连接异常时重连一次
+		_ = s.conn.Close()
+		s.conn = nil
+		if err = s.dial(); err != nil {
+			return err
+		}
+		_, err = s.conn.Write(msg)
+		return err
+	}
+	return nil
+}
+
+func (s *netSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
@@ -0,0 +1,27 @@
+package core
+
+import (
+	"gpm/app/service/mail"
+	"gpm/global"
+
+	"github.com/sirupsen/logrus"
+)
+
+// InitMail 按配置选择邮件发送实现
+func InitMail() {
+	mc := global.Config.Mail
+	switch mc.Type {
+	case "smtp":
+		mail.Sender = mail.SMTPMailer{Host: mc.Host, Port: mc.Port, Username: mc.Username, Password: mc.Password, From: mc.From}
+	case "file":
+		dir := mc.Dir
+		if dir == "" {
+			dir = "mails"
+		}
+		mail.Sender = mail.FileMailer{Dir: dir, From: mc.From}
+	case "", "log":
+		mail.Sender = mail.LogMailer{}
+	default:
+		logrus.Fatalf("未知的邮件发送方式: %s", mc.Type)
+	}
+}
@@ -0,0 +1,20 @@
+package core
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+)
+
+// InitSignal 收到中断或终止信号时经 logrus.Exit 退出，执行已注册的退出处理（刷新日志输出端、关闭遥测导出）
+func InitSignal() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-ch
+		logrus.Infof("收到信号 %s，退出", sig)
+		logrus.Exit(0)
+	}()
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"gpm/app/service/telemetry"
+	"gpm/global"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+// InitTelemetry 初始化链路追踪与指标，未启用时埋点均为空实现
+func InitTelemetry() {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	tc := global.Config.Telemetry
+	if !tc.Enable {
+		if err := telemetry.InitInstruments(); err != nil {
+			logrus.Fatalf("指标初始化失败: %s", err)
+		}
+		return
+	}
+	ctx := context.Background()
+	traceExporter, metricExporter, err := newTelemetryExporters(ctx)
+	if err != nil {
+		logrus.Fatalf("OpenTelemetry 导出器初始化失败: %s", err)
+	}
+	serviceName := tc.ServiceName
+	if serviceName == "" {
+		serviceName = global.Config.Log.App
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(serviceName),
+		semconv.DeploymentEnvironmentName(global.Config.System.Env),
+	))
+	if err != nil {
+		logrus.Fatalf("OpenTelemetry 资源初始化失败: %s", err)
+	}
+	ratio := tc.SampleRatio
+	if ratio <= 0 {
+		ratio = 1
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	interval := tc.MetricInterval
+	if interval <= 0 {
+		interval = 60
+	}
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(time.Duration(interval)*time.Second))),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetMeterProvider(mp)
+	if err = telemetry.InitInstruments(); err != nil {
+		logrus.Fatalf("指标初始化失败: %s", err)
+	}
+	if sqlDB, err := global.DB.DB(); err == nil {
+		if err = telemetry.RegisterDBStats(sqlDB); err != nil {
+			logrus.Warnf("连接池指标注册失败: %s", err)
+		}
+	}
+	logrus.RegisterExitHandler(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = tp.Shutdown(ctx)
+		_ = mp.Shutdown(ctx)
+	})
+	logrus.Infof("OpenTelemetry 初始化成功，导出器 %s", tc.Exporter)
+}
+
+func newTelemetryExporters(ctx context.Context) (sdktrace.SpanExporter, sdkmetric.Exporter, error) {
+	tc := global.Config.Telemetry
+	switch tc.Exporter {
+	case "", "stdout":
+		traceExporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, nil, err
+		}
+		metricExporter, err := stdoutmetric.New()
+		if err != nil {
+			return nil, nil, err
+		}
+		return traceExporter, metricExporter, nil
+	case "otlp":
+		traceOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(tc.Endpoint)}
+		metricOpts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(tc.Endpoint)}
+		if tc.Insecure {
+			traceOpts = append(traceOpts, otlptracehttp.WithInsecure())
+			metricOpts = append(metricOpts, otlpmetrichttp.WithInsecure())
+		}
+		traceExporter, err := otlptracehttp.New(ctx, traceOpts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		metricExporter, err := otlpmetrichttp.New(ctx, metricOpts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		return traceExporter, metricExporter, nil
+	default:
+		return nil, nil, fmt.Errorf("未知的 OpenTelemetry 导出器: %s", tc.Exporter)
+	}
+}
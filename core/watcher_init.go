@@ -0,0 +1,37 @@
+package core
+
+import (
+	"context"
+	"gpm/app/service/watcher"
+	"gpm/global"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// InitWatcher 启用多实例策略同步，本实例的变更由 Enforcer 自动广播
+func InitWatcher() {
+	wc := global.Config.Watcher
+	t, err := watcher.NewTransport(wc)
+	if err != nil {
+		logrus.Fatalf("策略同步初始化失败: %s", err)
+	}
+	if t == nil {
+		return
+	}
+	interval := wc.PollInterval
+	if interval <= 0 {
+		interval = 5
+		if wc.Type == "postgres" {
+			interval = 60
+		}
+	}
+	w := watcher.New(global.CasbinEnforcer, t)
+	if err = global.CasbinEnforcer.SetWatcher(w); err != nil {
+		logrus.Fatalf("策略同步初始化失败: %s", err)
+	}
+	if err = w.Start(context.Background(), time.Duration(interval)*time.Second); err != nil {
+		logrus.Fatalf("策略同步初始化失败: %s", err)
+	}
+	logrus.Infof("策略同步已启用，方式 %s，轮询间隔%d秒", wc.Type, interval)
+}
@@ -0,0 +1,60 @@
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"gpm/app/service/audit"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FlagsVerifyLog 校验操作日志哈希链，存在断裂时以非零状态码退出
+func FlagsVerifyLog(tenant string) {
+	ctx := context.Background()
+	tenants := []string{tenant}
+	if tenant == "" {
+		var err error
+		tenants, err = audit.Tenants(ctx)
+		if err != nil {
+			logrus.Fatal(err)
+			return
+		}
+	}
+	broken := false
+	for _, t := range tenants {
+		result, err := audit.Verify(ctx, t)
+		if err != nil {
+			logrus.Fatal(err)
+			return
+		}
+		if result.Broken != nil {
+			broken = true
+			logrus.Errorf("租户 %s 哈希链断裂: seq=%d logId=%s %s", t, result.Broken.Seq, result.Broken.LogID, result.Broken.Reason)
+			continue
+		}
+		logrus.Infof("租户 %s 哈希链完好，共%d条，链头 %s", t, result.Count, result.Head)
+	}
+	if broken {
+		logrus.Exit(1)
+	}
+}
+
+// FlagsExportCheckpoint 导出签名检查点到文件
+func FlagsExportCheckpoint(file string, tenant string) {
+	export, err := audit.ExportCheckpoints(context.Background(), tenant)
+	if err != nil {
+		logrus.Fatal(err)
+		return
+	}
+	byteData, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		logrus.Fatal(err)
+		return
+	}
+	if err = os.WriteFile(file, byteData, 0600); err != nil {
+		logrus.Fatal(err)
+		return
+	}
+	logrus.Infof("已导出%d个检查点到 %s", len(export.Checkpoints), file)
+}
@@ -0,0 +1,68 @@
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gpm/app/service/bundle"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FlagsPolicyExport 导出租户权限配置，.csv 文件只包含策略与角色分配
+func FlagsPolicyExport(file string, tenant string) {
+	if tenant == "" {
+		logrus.Fatal("请通过 -tenant 指定租户")
+		return
+	}
+	b, err := bundle.Export(context.Background(), tenant)
+	if err != nil {
+		logrus.Fatal(err)
+		return
+	}
+	byteData, err := bundle.Encode(b, bundle.FormatOf(file))
+	if err != nil {
+		logrus.Fatal(err)
+		return
+	}
+	if err = os.WriteFile(file, byteData, 0644); err != nil {
+		logrus.Fatal(err)
+		return
+	}
+	logrus.Infof("已导出租户 %s 的%d条授权、%d条角色分配到 %s", b.Tenant, len(b.Policies), len(b.Groupings), file)
+}
+
+// FlagsPolicyImport 导入租户权限配置，默认仅输出差异，加 -apply 后写入
+func FlagsPolicyImport(file string, tenant string, apply bool, prune bool) {
+	if tenant == "" {
+		logrus.Fatal("请通过 -tenant 指定租户")
+		return
+	}
+	byteData, err := os.ReadFile(file)
+	if err != nil {
+		logrus.Fatal(err)
+		return
+	}
+	b, err := bundle.Decode(byteData, bundle.FormatOf(file))
+	if err != nil {
+		logrus.Fatal(err)
+		return
+	}
+	diff, err := bundle.Import(context.Background(), tenant, b, bundle.Options{Apply: apply, Prune: prune})
+	if err != nil {
+		logrus.Fatal(err)
+		return
+	}
+	byteData, err = json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		logrus.Fatal(err)
+		return
+	}
+	fmt.Println(string(byteData))
+	if diff.Applied {
+		logrus.Infof("已导入 %s", file)
+	} else {
+		logrus.Info("预演完成，确认差异后加 -apply 写入")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"gpm/app/model"
 	"gpm/global"
+	"time"
 
 	gormadapter "github.com/casbin/gorm-adapter/v3"
 	"github.com/sirupsen/logrus"
@@ -22,22 +23,51 @@ func FlagsDb() {
 			return
 		}
 	}()
+	// 新增 verified_at 列时，已有账号视为已验证
+	backfillVerified := !global.DB.Migrator().HasColumn(&model.User{}, "VerifiedAt")
 	err = global.DB.AutoMigrate(
 		&model.User{},
 		&model.Role{},
 		&model.Tenant{},
 		&model.ActionLog{},
+		&model.ActionLogCheckpoint{},
 		&model.Tenant{},
 		&model.Menu{},
 		&gormadapter.CasbinRule{},
+		&model.CasbinVersion{},
 		&model.Api{},
 		&model.Doc{},
 		&model.DocDir{},
+		&model.UserIdentity{},
+		&model.UserMfa{},
+		&model.UserToken{},
+		&model.UserSession{},
+		&model.TemporaryGrant{},
+		&model.MfaRecoveryCode{},
+		&model.MfaPolicy{},
 		&model.UserBlack{},
 		&model.TokenBlack{},
+		&model.TenantSignKey{},
+		&model.ServiceAccount{},
+		&model.ApiKey{},
+		&model.SigningKey{},
+		&model.OAuthClient{},
 	)
 	if err != nil {
 		logrus.Fatal(err)
 		return
 	}
+	if backfillVerified {
+		if err = migrateUserVerified(); err != nil {
+			logrus.Fatal(err)
+		}
+	}
+}
+
+// migrateUserVerified 已有账号标记为已验证并启用
+// 此前 status 仅有默认值 false 且登录不检查，保持这些账号可以继续登录
+func migrateUserVerified() error {
+	now := int(time.Now().Unix())
+	return global.DB.Model(&model.User{}).Where("verified_at = 0").
+		Updates(map[string]any{"verified_at": now, "status": true}).Error
 }
@@ -3,25 +3,78 @@ package flags
 import (
 	"flag"
 	"os"
+
+	"github.com/sirupsen/logrus"
 )
 
 type Options struct {
-	File    string
-	DB      bool
-	Version bool
+	File             string
+	DB               bool
+	Version          bool
+	VerifyLog        bool
+	ExportCheckpoint string
+	Tenant           string
+	RotateKey        bool
+	PolicyExport     string
+	PolicyImport     string
+	Apply            bool
+	Prune            bool
+	IssueSignKey     bool
+	Remark           string
 }
 
 var FlagOptions = new(Options)
 
 func Parse() {
+	// 子命令，不依赖配置文件与数据库
+	if len(os.Args) > 1 && os.Args[1] == "sign" {
+		RunSign(os.Args[2:])
+		os.Exit(0)
+	}
 	flag.BoolVar(&FlagOptions.DB, "db", false, "数据库迁移")
 	flag.StringVar(&FlagOptions.File, "f", "settings.yaml", "配置文件")
 	flag.BoolVar(&FlagOptions.Version, "v", false, "版本")
+	flag.BoolVar(&FlagOptions.VerifyLog, "verify_log", false, "校验操作日志哈希链")
+	flag.StringVar(&FlagOptions.ExportCheckpoint, "export_checkpoint", "", "导出操作日志签名检查点到指定文件")
+	flag.StringVar(&FlagOptions.Tenant, "tenant", "", "限定租户（为空表示全部）")
+	flag.BoolVar(&FlagOptions.RotateKey, "rotate_key", false, "立即轮换令牌签名密钥")
+	flag.StringVar(&FlagOptions.PolicyExport, "policy_export", "", "导出租户权限配置到指定文件（.yaml 或 .csv）")
+	flag.StringVar(&FlagOptions.PolicyImport, "policy_import", "", "从指定文件导入租户权限配置，默认仅预演")
+	flag.BoolVar(&FlagOptions.Apply, "apply", false, "导入时写入数据库")
+	flag.BoolVar(&FlagOptions.Prune, "prune", false, "导入时删除配置包中不存在的数据")
+	flag.BoolVar(&FlagOptions.IssueSignKey, "issue_sign_key", false, "为 -tenant 指定的租户签发请求签名密钥")
+	flag.StringVar(&FlagOptions.Remark, "remark", "", "签发密钥的备注")
 	flag.Parse()
 }
+
+// Run 执行命令行指定的一次性任务后退出，经 logrus.Exit 退出以执行退出处理（刷新日志输出端等）
 func Run() {
 	if FlagOptions.DB {
 		FlagsDb()
-		os.Exit(0)
+		logrus.Exit(0)
+	}
+	if FlagOptions.VerifyLog {
+		FlagsVerifyLog(FlagOptions.Tenant)
+		logrus.Exit(0)
+	}
+	if FlagOptions.ExportCheckpoint != "" {
+		FlagsExportCheckpoint(FlagOptions.ExportCheckpoint, FlagOptions.Tenant)
+		logrus.Exit(0)
+	}
+	if FlagOptions.RotateKey {
+		FlagsRotateKey()
+		logrus.Exit(0)
+	}
+	if FlagOptions.PolicyExport != "" {
+		FlagsPolicyExport(FlagOptions.PolicyExport, FlagOptions.Tenant)
+		logrus.Exit(0)
+	}
+	if FlagOptions.PolicyImport != "" {
+		FlagsPolicyImport(FlagOptions.PolicyImport, FlagOptions.Tenant, FlagOptions.Apply, FlagOptions.Prune)
+		logrus.Exit(0)
+	}
+	if FlagOptions.IssueSignKey {
+		FlagsIssueSignKey(FlagOptions.Tenant, FlagOptions.Remark)
+		logrus.Exit(0)
 	}
 }
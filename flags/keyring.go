@@ -0,0 +1,26 @@
+package flags
+
+import (
+	"context"
+	"gpm/app/service/keyring"
+	"gpm/global"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FlagsRotateKey 立即轮换令牌签名密钥，旧公钥按 jwt.rotateGrace 保留
+func FlagsRotateKey() {
+	ctx := context.Background()
+	grace := time.Duration(global.Config.Jwt.RotateGrace) * time.Second
+	if _, err := keyring.Rotate(ctx, grace, 0); err != nil {
+		logrus.Fatal(err)
+		return
+	}
+	key, err := keyring.Current(ctx)
+	if err != nil {
+		logrus.Fatal(err)
+		return
+	}
+	logrus.Infof("签名密钥已轮换，当前 kid=%s alg=%s", key.Kid, key.Alg)
+}
@@ -0,0 +1,25 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"gpm/app/service/signature"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FlagsIssueSignKey 为租户签发签名密钥，用于关闭 argsCheck.legacy 前发放首个密钥
+// 签名接口本身需要签名，首个密钥只能通过命令行签发
+func FlagsIssueSignKey(tenant string, remark string) {
+	if tenant == "" {
+		logrus.Fatal("请通过 -tenant 指定租户")
+		return
+	}
+	key, err := signature.IssueKey(context.Background(), tenant, remark, 0)
+	if err != nil {
+		logrus.Fatal(err)
+		return
+	}
+	// 密钥只在签发时可见，直接输出到终端，不写入日志
+	fmt.Printf("key-id: %s\nsecret: %s\n", key.KeyID, key.Secret)
+}
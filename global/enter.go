@@ -9,5 +9,5 @@ import (
 var (
 	Config         *conf.Config
 	DB             *gorm.DB
-	CasbinEnforcer *casbin.Enforcer
+	CasbinEnforcer *casbin.SyncedEnforcer
 )
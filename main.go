@@ -11,8 +11,15 @@ func main() {
 	flags.Parse()
 	core.ReadConf()
 	core.InitLogrus()
+	core.InitSignal()
 	global.DB = core.InitDB()
+	core.InitTelemetry()
+	core.InitMail()
 	flags.Run()
 	global.CasbinEnforcer = core.InitCasbin()
+	core.InitWatcher()
+	core.InitGrant()
+	core.InitAudit()
+	core.InitKeyring()
 	router.Run()
 }